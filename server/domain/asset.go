@@ -13,12 +13,16 @@ func NewAssetID() AssetID {
 }
 
 type Asset struct {
-	ID         AssetID
-	UserID     UserID
-	Name       string
-	CategoryID *AssetCategoryID
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID            AssetID
+	UserID        UserID
+	Name          string
+	CategoryID    *AssetCategoryID
+	Archived      bool // アーカイブしたAssetは一覧に表示しないが、過去のRecordには残す
+	SortOrder     int  // ユーザーが指定した並び順（昇順）
+	BusinessRatio *int // このAssetの支出の事業割合の既定値（%）。nilの場合は既定値なし
+	Version       int  // 更新のたびに増える。楽観的排他制御に使う
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func NewAsset(userID UserID, name string, categoryID *AssetCategoryID) *Asset {
@@ -27,7 +31,28 @@ func NewAsset(userID UserID, name string, categoryID *AssetCategoryID) *Asset {
 		UserID:     userID,
 		Name:       name,
 		CategoryID: categoryID,
+		Version:    1,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 }
+
+type AssetConnection struct {
+	Nodes           []*Asset
+	PageInfo        *PageInfo
+	CategoryID      *AssetCategoryID // totalCountの集計に使う絞り込み条件
+	IncludeArchived bool
+}
+
+func UniqueAssetIDs(ids []AssetID) []AssetID {
+	uniqueIDs := make([]AssetID, 0, len(ids))
+	seen := make(map[AssetID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+	return uniqueIDs
+}
@@ -1,27 +1,72 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	AssetCategoryIDSuffix = "AssetCategory"
+)
 
 type AssetCategoryID string
 
 func NewAssetCategoryID() AssetCategoryID {
-	return AssetCategoryID(NewUUIDv4("AssetCategory"))
+	return AssetCategoryID(NewUUIDv4(AssetCategoryIDSuffix))
 }
 
 type AssetCategory struct {
 	ID        AssetCategoryID
 	UserID    UserID
 	Name      string
+	ParentID  *AssetCategoryID // nilの場合は最上位のカテゴリ
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
-func NewAssetCategory(userID UserID, name string) *AssetCategory {
+func NewAssetCategory(userID UserID, name string, parentID *AssetCategoryID) *AssetCategory {
 	return &AssetCategory{
 		ID:        NewAssetCategoryID(),
 		UserID:    userID,
 		Name:      name,
+		ParentID:  parentID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 }
+
+// ValidateParent は親のカテゴリとして設定できるかを検証する。自身または子孫を親にして循環する場合はエラーを返す
+func (c *AssetCategory) ValidateParent(parentID *AssetCategoryID, categories AssetCategories) error {
+	if parentID == nil {
+		return nil
+	}
+
+	for _, id := range categories.DescendantIDs(c.ID) {
+		if id == *parentID {
+			return xerrors.Errorf("asset category cannot be moved under itself or its descendants: %w", ErrInvalidAssetCategory)
+		}
+	}
+
+	return nil
+}
+
+type AssetCategories []*AssetCategory
+
+// DescendantIDs は指定したカテゴリとその子孫のカテゴリのIDを返す
+func (categories AssetCategories) DescendantIDs(id AssetCategoryID) []AssetCategoryID {
+	childIDs := make(map[AssetCategoryID][]AssetCategoryID)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			childIDs[*category.ParentID] = append(childIDs[*category.ParentID], category.ID)
+		}
+	}
+
+	return collectDescendantIDs(id, childIDs)
+}
+
+type AssetCategoryConnection struct {
+	Nodes    []*AssetCategory
+	PageInfo *PageInfo
+	RootOnly bool // totalCountの集計で最上位のカテゴリのみを数えるか
+}
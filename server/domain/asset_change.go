@@ -54,6 +54,20 @@ func (changes AssetChanges) Expense() *AssetChange {
 	return nil
 }
 
+// AssetIDs は重複を除いたAssetIDを返す
+func (changes AssetChanges) AssetIDs() []AssetID {
+	ids := make([]AssetID, 0, len(changes))
+	seen := make(map[AssetID]bool, len(changes))
+	for _, change := range changes {
+		if seen[change.AssetID] {
+			continue
+		}
+		seen[change.AssetID] = true
+		ids = append(ids, change.AssetID)
+	}
+	return ids
+}
+
 type AssetChangeWithAt struct {
 	AssetChange
 	At time.Time // RecordのAtを持つ
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	AttachmentIDSuffix = "Attachment"
+
+	// AttachmentMaxSize は1ファイルの上限（バイト）
+	AttachmentMaxSize = 10 << 20
+	// AttachmentQuotaPerUser はユーザーごとに保存できる添付ファイルの合計サイズの上限（バイト）
+	AttachmentQuotaPerUser = 1 << 30
+	// AttachmentURLExpiresIn はダウンロード用の署名付きURLの有効期間
+	AttachmentURLExpiresIn = 15 * time.Minute
+
+	attachmentFileNameMaxLength = 255
+)
+
+// attachmentExtensions は添付できるファイルの種類と保存時の拡張子。領収書の写真とPDFの請求書を想定する
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type AttachmentID string
+
+func NewAttachmentID() AttachmentID {
+	return AttachmentID(NewUUIDv4(AttachmentIDSuffix))
+}
+
+// Attachment はRecordに添付したファイル（領収書の写真、請求書のPDFなど）
+type Attachment struct {
+	ID          AttachmentID
+	UserID      UserID
+	RecordID    *RecordID // Recordを削除するとnilになり、ファイルとあわせて削除される
+	FileName    string
+	ContentType string
+	Size        int
+	StorageKey  string // Storageでの保存先
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewAttachment はファイルの内容から判定したcontentTypeが添付できる種類で、サイズが上限以下の場合のみ作成する
+func NewAttachment(userID UserID, recordID RecordID, fileName string, contentType string, size int) (*Attachment, error) {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, xerrors.Errorf("content type %s is not allowed: %w", contentType, ErrInvalidAttachment)
+	}
+	if size <= 0 || size > AttachmentMaxSize {
+		return nil, xerrors.Errorf("file size must be between 1 and %d bytes: %w", AttachmentMaxSize, ErrInvalidAttachment)
+	}
+
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	if fileName == "." || fileName == "/" {
+		fileName = "attachment" + extension
+	}
+	if utf8.RuneCountInString(fileName) > attachmentFileNameMaxLength {
+		fileName = string([]rune(fileName)[:attachmentFileNameMaxLength])
+	}
+
+	id := NewAttachmentID()
+	return &Attachment{
+		ID:          id,
+		UserID:      userID,
+		RecordID:    &recordID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  string(userID) + "/" + string(id) + extension,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// AttachmentUsage はユーザーの添付ファイルの使用量
+type AttachmentUsage struct {
+	UsedBytes  int
+	QuotaBytes int
+}
+
+// ValidateQuota はsizeのファイルを追加しても上限を超えないかを検証する
+func (u *AttachmentUsage) ValidateQuota(size int) error {
+	if u.UsedBytes+size > u.QuotaBytes {
+		return xerrors.Errorf("used %d of %d bytes: %w", u.UsedBytes, u.QuotaBytes, ErrAttachmentQuotaExceeded)
+	}
+
+	return nil
+}
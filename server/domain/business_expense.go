@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// businessRatioMax は事業割合の上限（%）
+const businessRatioMax = 100
+
+// ValidateBusinessRatio は事業割合が0〜100%かを検証する。nilは未設定を表す
+func ValidateBusinessRatio(ratio *int) error {
+	if ratio != nil && (*ratio < 0 || *ratio > businessRatioMax) {
+		return xerrors.Errorf("business ratio must be between 0 and %d: %w", businessRatioMax, ErrInvalidBusinessRatio)
+	}
+
+	return nil
+}
+
+// SetBusinessRatio はRecordの事業割合を設定する。事業割合は支出にのみ設定できる
+func (r *Record) SetBusinessRatio(ratio *int) error {
+	if ratio != nil && r.RecordType != RecordTypeExpense {
+		return xerrors.Errorf("%s record cannot have business ratio: %w", r.RecordType, ErrInvalidBusinessRatio)
+	}
+	err := ValidateBusinessRatio(ratio)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	r.BusinessRatio = ratio
+
+	return nil
+}
+
+// EffectiveBusinessRatio は家事按分に使う事業割合を返す
+// Recordに設定した割合、Tagの既定値（複数ある場合は最大）、Assetの既定値の順に使い、いずれもない場合は0
+func (r *Record) EffectiveBusinessRatio(tags []*Tag, asset *Asset) int {
+	if r.BusinessRatio != nil {
+		return *r.BusinessRatio
+	}
+
+	var tagRatio *int
+	for _, tag := range tags {
+		if tag.BusinessRatio != nil && (tagRatio == nil || *tag.BusinessRatio > *tagRatio) {
+			tagRatio = tag.BusinessRatio
+		}
+	}
+	if tagRatio != nil {
+		return *tagRatio
+	}
+
+	if asset != nil && asset.BusinessRatio != nil {
+		return *asset.BusinessRatio
+	}
+
+	return 0
+}
+
+// ExpenseAccount は青色申告決算書（一般用）の経費の科目
+type ExpenseAccount string
+
+const (
+	ExpenseAccountTaxesAndDues         ExpenseAccount = "TAXES_AND_DUES"         // 租税公課
+	ExpenseAccountPackingAndShipping   ExpenseAccount = "PACKING_AND_SHIPPING"   // 荷造運賃
+	ExpenseAccountUtilities            ExpenseAccount = "UTILITIES"              // 水道光熱費
+	ExpenseAccountTravel               ExpenseAccount = "TRAVEL"                 // 旅費交通費
+	ExpenseAccountCommunication        ExpenseAccount = "COMMUNICATION"          // 通信費
+	ExpenseAccountAdvertising          ExpenseAccount = "ADVERTISING"            // 広告宣伝費
+	ExpenseAccountEntertainment        ExpenseAccount = "ENTERTAINMENT"          // 接待交際費
+	ExpenseAccountInsurance            ExpenseAccount = "INSURANCE"              // 損害保険料
+	ExpenseAccountRepairs              ExpenseAccount = "REPAIRS"                // 修繕費
+	ExpenseAccountSupplies             ExpenseAccount = "SUPPLIES"               // 消耗品費
+	ExpenseAccountDepreciation         ExpenseAccount = "DEPRECIATION"           // 減価償却費
+	ExpenseAccountWelfare              ExpenseAccount = "WELFARE"                // 福利厚生費
+	ExpenseAccountSalaries             ExpenseAccount = "SALARIES"               // 給料賃金
+	ExpenseAccountOutsourcing          ExpenseAccount = "OUTSOURCING"            // 外注工賃
+	ExpenseAccountInterestAndDiscounts ExpenseAccount = "INTEREST_AND_DISCOUNTS" // 利子割引料
+	ExpenseAccountRent                 ExpenseAccount = "RENT"                   // 地代家賃
+	ExpenseAccountBadDebts             ExpenseAccount = "BAD_DEBTS"              // 貸倒金
+	ExpenseAccountMiscellaneous        ExpenseAccount = "MISCELLANEOUS"          // 雑費
+)
+
+// expenseAccountLines は決算書の並び順と行番号
+// 貸倒金（24）と雑費（31）の間の空欄の行（25〜30）には、科目を設定していない分類を記入する
+var expenseAccountLines = []struct {
+	account ExpenseAccount
+	label   string
+	line    int
+}{
+	{ExpenseAccountTaxesAndDues, "租税公課", 8},
+	{ExpenseAccountPackingAndShipping, "荷造運賃", 9},
+	{ExpenseAccountUtilities, "水道光熱費", 10},
+	{ExpenseAccountTravel, "旅費交通費", 11},
+	{ExpenseAccountCommunication, "通信費", 12},
+	{ExpenseAccountAdvertising, "広告宣伝費", 13},
+	{ExpenseAccountEntertainment, "接待交際費", 14},
+	{ExpenseAccountInsurance, "損害保険料", 15},
+	{ExpenseAccountRepairs, "修繕費", 16},
+	{ExpenseAccountSupplies, "消耗品費", 17},
+	{ExpenseAccountDepreciation, "減価償却費", 18},
+	{ExpenseAccountWelfare, "福利厚生費", 19},
+	{ExpenseAccountSalaries, "給料賃金", 20},
+	{ExpenseAccountOutsourcing, "外注工賃", 21},
+	{ExpenseAccountInterestAndDiscounts, "利子割引料", 22},
+	{ExpenseAccountRent, "地代家賃", 23},
+	{ExpenseAccountBadDebts, "貸倒金", 24},
+	{ExpenseAccountMiscellaneous, "雑費", 31},
+}
+
+const (
+	expenseAccountCustomFirstLine = 25
+	expenseAccountCustomLastLine  = 30
+	expenseAccountTotalLine       = 32
+
+	uncategorizedExpenseLineName = "未分類"
+)
+
+func (a ExpenseAccount) IsValid() bool {
+	for _, line := range expenseAccountLines {
+		if line.account == a {
+			return true
+		}
+	}
+	return false
+}
+
+// SetExpenseAccount は分類に決算書の科目を設定する。科目は支出の分類にのみ設定できる
+func (c *RecordCategory) SetExpenseAccount(account *ExpenseAccount) error {
+	if account != nil {
+		if c.RecordType != RecordTypeExpense {
+			return xerrors.Errorf("expense account cannot be set to %s category: %w", c.RecordType, ErrInvalidRecordCategory)
+		}
+		if !account.IsValid() {
+			return xerrors.Errorf("expense account %s is not supported: %w", *account, ErrInvalidRecordCategory)
+		}
+	}
+
+	c.ExpenseAccount = account
+
+	return nil
+}
+
+// expenseAccountOf は分類の科目を返す。分類に科目がない場合は最も近い祖先の科目を使う
+func expenseAccountOf(categoryMap map[RecordCategoryID]*RecordCategory, id RecordCategoryID) (*ExpenseAccount, *RecordCategory) {
+	category := categoryMap[id]
+	root := category
+	for category != nil {
+		if category.ExpenseAccount != nil {
+			return category.ExpenseAccount, category
+		}
+		root = category
+		if category.ParentID == nil {
+			break
+		}
+		category = categoryMap[*category.ParentID]
+	}
+
+	return nil, root
+}
+
+// BusinessExpenseLine は決算書の経費の1行
+type BusinessExpenseLine struct {
+	Account     *ExpenseAccount // nilの場合は科目を設定していない分類（空欄の行に記入する）
+	Name        string
+	Amount      int // 事業割合を掛けた金額
+	RecordCount int
+}
+
+// BusinessExpenseReport は確定申告のための年間の経費の集計
+type BusinessExpenseReport struct {
+	Year  int
+	From  time.Time
+	To    time.Time
+	Lines []*BusinessExpenseLine // 決算書の並び順
+	Total int
+}
+
+// TaxYearRange は課税期間（1月1日から12月31日まで）を返す。toは翌年の1月1日
+func TaxYearRange(year int) (time.Time, time.Time) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	return from, from.AddDate(1, 0, 0)
+}
+
+// NewBusinessExpenseReport は支出のRecordに事業割合を掛けた金額を、分類に設定した決算書の科目ごとに集計する
+// 事業割合を掛けた金額の1円未満は切り捨てる。科目がない分類は最上位の分類ごとに、分類のないRecordは未分類としてまとめる
+func NewBusinessExpenseReport(year int, records Records, changes AssetChanges, tags []*TagWithRecordID, assets []*Asset, categories RecordCategories) *BusinessExpenseReport {
+	from, to := TaxYearRange(year)
+
+	changeMap := make(map[RecordID]AssetChanges, len(records))
+	for _, change := range changes {
+		changeMap[change.RecordID] = append(changeMap[change.RecordID], change)
+	}
+	tagMap := make(map[RecordID][]*Tag, len(records))
+	for _, tag := range tags {
+		tagMap[tag.RecordID] = append(tagMap[tag.RecordID], &tag.Tag)
+	}
+	assetMap := make(map[AssetID]*Asset, len(assets))
+	for _, asset := range assets {
+		assetMap[asset.ID] = asset
+	}
+	categoryMap := make(map[RecordCategoryID]*RecordCategory, len(categories))
+	for _, category := range categories {
+		categoryMap[category.ID] = category
+	}
+
+	accountLines := make(map[ExpenseAccount]*BusinessExpenseLine, len(expenseAccountLines))
+	lines := make([]*BusinessExpenseLine, 0, len(expenseAccountLines))
+	for _, line := range expenseAccountLines {
+		accountLine := &BusinessExpenseLine{Account: &line.account, Name: line.label}
+		accountLines[line.account] = accountLine
+		lines = append(lines, accountLine)
+	}
+	customLines := make(map[string]*BusinessExpenseLine)
+	customNames := make([]string, 0)
+
+	report := &BusinessExpenseReport{Year: year, From: from, To: to}
+	for _, record := range records {
+		change := changeMap[record.ID].Expense()
+		if record.RecordType != RecordTypeExpense || change == nil {
+			continue
+		}
+
+		ratio := record.EffectiveBusinessRatio(tagMap[record.ID], assetMap[change.AssetID])
+		if ratio == 0 {
+			continue
+		}
+		amount := -change.Amount * ratio / businessRatioMax
+
+		var line *BusinessExpenseLine
+		name := uncategorizedExpenseLineName
+		if record.CategoryID != nil {
+			account, category := expenseAccountOf(categoryMap, *record.CategoryID)
+			if account != nil {
+				line = accountLines[*account]
+			} else if category != nil {
+				name = category.Name
+			}
+		}
+		if line == nil {
+			line = customLines[name]
+			if line == nil {
+				line = &BusinessExpenseLine{Name: name}
+				customLines[name] = line
+				customNames = append(customNames, name)
+			}
+		}
+
+		line.Amount += amount
+		line.RecordCount++
+		report.Total += amount
+	}
+
+	// 空欄の行は雑費の前に、名前順で並べる
+	sort.Strings(customNames)
+	custom := make([]*BusinessExpenseLine, 0, len(customNames))
+	for _, name := range customNames {
+		custom = append(custom, customLines[name])
+	}
+	miscellaneous := len(lines) - 1
+	report.Lines = append(append(lines[:miscellaneous:miscellaneous], custom...), lines[miscellaneous])
+
+	return report
+}
+
+// CSV は決算書の経費の欄と同じ並びで「番号,科目,金額」のCSVを返す
+// 空欄の行（25〜30）を超える科目のない分類は番号を空にして続けて出力する
+func (r *BusinessExpenseReport) CSV() (string, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+
+	rows := [][]string{{"番号", "科目", "金額"}}
+	customLine := expenseAccountCustomFirstLine
+	for _, line := range r.Lines {
+		number := ""
+		if line.Account != nil {
+			for _, accountLine := range expenseAccountLines {
+				if accountLine.account == *line.Account {
+					number = strconv.Itoa(accountLine.line)
+				}
+			}
+		} else if customLine <= expenseAccountCustomLastLine {
+			number = strconv.Itoa(customLine)
+			customLine++
+		}
+		rows = append(rows, []string{number, line.Name, strconv.Itoa(line.Amount)})
+	}
+	rows = append(rows, []string{strconv.Itoa(expenseAccountTotalLine), "計", strconv.Itoa(r.Total)})
+
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return "", xerrors.Errorf("failed to write business expense report csv: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"kakeibo-web-server/lib/typeutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordEffectiveBusinessRatio(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *int
+		tags   []*int
+		asset  *int
+		want   int
+	}{
+		{"record", typeutil.Ptr(30), []*int{typeutil.Ptr(80)}, typeutil.Ptr(90), 30},
+		{"record zero", typeutil.Ptr(0), []*int{typeutil.Ptr(80)}, typeutil.Ptr(90), 0},
+		{"max of tags", nil, []*int{typeutil.Ptr(20), nil, typeutil.Ptr(60)}, typeutil.Ptr(90), 60},
+		{"asset", nil, []*int{nil}, typeutil.Ptr(90), 90},
+		{"none", nil, nil, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &Record{RecordType: RecordTypeExpense, BusinessRatio: tt.record}
+			tags := make([]*Tag, 0, len(tt.tags))
+			for _, ratio := range tt.tags {
+				tags = append(tags, &Tag{BusinessRatio: ratio})
+			}
+			asset := &Asset{BusinessRatio: tt.asset}
+
+			got := record.EffectiveBusinessRatio(tags, asset)
+			if got != tt.want {
+				t.Errorf("EffectiveBusinessRatio() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBusinessExpenseReport(t *testing.T) {
+	communication := ExpenseAccountCommunication
+	categories := RecordCategories{
+		{ID: "phone", RecordType: RecordTypeExpense, Name: "通信", ExpenseAccount: &communication},
+		{ID: "mobile", RecordType: RecordTypeExpense, Name: "携帯", ParentID: typeutil.Ptr(RecordCategoryID("phone"))},
+		{ID: "books", RecordType: RecordTypeExpense, Name: "書籍"},
+		{ID: "magazines", RecordType: RecordTypeExpense, Name: "雑誌", ParentID: typeutil.Ptr(RecordCategoryID("books"))},
+		{ID: "car", RecordType: RecordTypeExpense, Name: "車両"},
+	}
+	assets := []*Asset{
+		{ID: testAssetA},
+		{ID: testAssetB, BusinessRatio: typeutil.Ptr(100)},
+	}
+
+	var records Records
+	var changes AssetChanges
+	var tags []*TagWithRecordID
+	addExpense := func(categoryID *RecordCategoryID, assetID AssetID, amount int, ratio *int, tagRatio *int) {
+		at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+		record, change, err := NewRecordExpenseWithAssetChange(testUserID, "title", "", at, assetID, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.CategoryID = categoryID
+		record.BusinessRatio = ratio
+		records = append(records, record)
+		changes = append(changes, change)
+		if tagRatio != nil {
+			tags = append(tags, &TagWithRecordID{Tag: Tag{BusinessRatio: tagRatio}, RecordID: record.ID})
+		}
+	}
+
+	// 子の分類は親の科目を使う。事業割合を掛けた1円未満は切り捨てる
+	addExpense(typeutil.Ptr(RecordCategoryID("mobile")), testAssetA, 3333, typeutil.Ptr(50), nil)
+	addExpense(typeutil.Ptr(RecordCategoryID("phone")), testAssetA, 1000, nil, typeutil.Ptr(30))
+	// 科目のない分類は最上位の分類の名前でまとめる
+	addExpense(typeutil.Ptr(RecordCategoryID("magazines")), testAssetB, 800, nil, nil)
+	addExpense(typeutil.Ptr(RecordCategoryID("books")), testAssetA, 1200, typeutil.Ptr(50), nil)
+	addExpense(typeutil.Ptr(RecordCategoryID("car")), testAssetA, 5000, typeutil.Ptr(10), nil)
+	addExpense(nil, testAssetB, 400, nil, nil)
+	// 事業割合が0のRecordは集計しない
+	addExpense(typeutil.Ptr(RecordCategoryID("car")), testAssetA, 9999, nil, nil)
+
+	income, incomeChange, err := NewRecordIncomeWithAssetChange(testUserID, "income", "", time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), testAssetB, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records = append(records, income)
+	changes = append(changes, incomeChange)
+
+	report := NewBusinessExpenseReport(2025, records, changes, tags, assets, categories)
+
+	if want := len(expenseAccountLines) + 3; len(report.Lines) != want {
+		t.Fatalf("len(Lines) = %d, want %d", len(report.Lines), want)
+	}
+	for i, line := range expenseAccountLines[:len(expenseAccountLines)-1] {
+		if report.Lines[i].Name != line.label {
+			t.Errorf("Lines[%d].Name = %s, want %s", i, report.Lines[i].Name, line.label)
+		}
+	}
+
+	wantTail := []struct {
+		name        string
+		amount      int
+		recordCount int
+	}{
+		{"書籍", 1400, 2},
+		{"未分類", 400, 1},
+		{"車両", 500, 1},
+		{"雑費", 0, 0},
+	}
+	tail := report.Lines[len(report.Lines)-len(wantTail):]
+	for i, want := range wantTail {
+		if tail[i].Name != want.name || tail[i].Amount != want.amount || tail[i].RecordCount != want.recordCount {
+			t.Errorf("Lines[%d] = {%s %d %d}, want %+v", len(report.Lines)-len(wantTail)+i, tail[i].Name, tail[i].Amount, tail[i].RecordCount, want)
+		}
+	}
+
+	communicationLine := report.Lines[4]
+	if communicationLine.Name != "通信費" || communicationLine.Amount != 1966 || communicationLine.RecordCount != 2 {
+		t.Errorf("communication line = %+v, want 通信費 1966 2", communicationLine)
+	}
+	if report.Total != 4266 {
+		t.Errorf("Total = %d, want %d", report.Total, 4266)
+	}
+}
+
+func TestBusinessExpenseReportCSV(t *testing.T) {
+	miscellaneous := ExpenseAccountMiscellaneous
+	tests := []struct {
+		name        string
+		customLines int
+		want        []string
+	}{
+		{
+			name:        "no custom lines",
+			customLines: 0,
+			want:        []string{"31,雑費,0"},
+		},
+		{
+			name:        "custom lines are numbered from 25",
+			customLines: 2,
+			want:        []string{"25,c0,0", "26,c1,0", "31,雑費,0"},
+		},
+		{
+			name:        "custom lines beyond 30 have no number",
+			customLines: 7,
+			want:        []string{"25,c0,0", "26,c1,0", "27,c2,0", "28,c3,0", "29,c4,0", "30,c5,0", ",c6,0", "31,雑費,0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &BusinessExpenseReport{Total: 123}
+			for i := 0; i < tt.customLines; i++ {
+				report.Lines = append(report.Lines, &BusinessExpenseLine{Name: "c" + strconv.Itoa(i)})
+			}
+			report.Lines = append(report.Lines, &BusinessExpenseLine{Account: &miscellaneous, Name: "雑費"})
+
+			got, err := report.CSV()
+			if err != nil {
+				t.Fatalf("CSV() error = %v", err)
+			}
+
+			want := append(append([]string{"番号,科目,金額"}, tt.want...), "32,計,123")
+			if got != strings.Join(want, "\n")+"\n" {
+				t.Errorf("CSV() = %q, want %q", got, strings.Join(want, "\n")+"\n")
+			}
+		})
+	}
+}
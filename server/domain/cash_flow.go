@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	CashFlowForecastMaxRange = 366 * 24 * time.Hour
+	CashFlowHistoryMonthsMin = 2
+	CashFlowHistoryMonthsMax = 24
+
+	// cashFlowRecurringRecentMonths は繰り返しとみなすために直近で発生しているべき月数。解約した定期支払いを予測に含めないため
+	cashFlowRecurringRecentMonths = 2
+)
+
+// CashFlowEntry はAssetの入出金1件。Amountは入金が正、出金が負
+type CashFlowEntry struct {
+	RecordID RecordID
+	At       time.Time
+	Name     string
+	PayeeID  *PayeeID
+	Amount   int
+}
+
+// NewCashFlowEntries はAssetChangeとRecordから入出金を作る。Recordが見つからないAssetChangeは除く
+func NewCashFlowEntries(changes AssetChangeWithAts, records Records) []*CashFlowEntry {
+	recordMap := make(map[RecordID]*Record, len(records))
+	for _, record := range records {
+		recordMap[record.ID] = record
+	}
+
+	entries := make([]*CashFlowEntry, 0, len(changes))
+	for _, change := range changes {
+		record, ok := recordMap[change.RecordID]
+		if !ok {
+			continue
+		}
+		entries = append(entries, &CashFlowEntry{
+			RecordID: record.ID,
+			At:       change.At,
+			Name:     record.Title,
+			PayeeID:  record.PayeeID,
+			Amount:   change.Amount,
+		})
+	}
+
+	return entries
+}
+
+// recurringKey は同じ繰り返しの入出金とみなすためのキー。支払先があれば支払先、なければタイトルの表記ゆれを無視して比較し、入金と出金は区別する
+func (e *CashFlowEntry) recurringKey() string {
+	direction := "+"
+	if e.Amount < 0 {
+		direction = "-"
+	}
+	if e.PayeeID != nil {
+		return direction + "payee:" + string(*e.PayeeID)
+	}
+	return direction + "title:" + normalizeForMatch(e.Name)
+}
+
+// RecurringCashFlow は履歴から検出した毎月繰り返す入出金
+type RecurringCashFlow struct {
+	Name       string // 最も新しい入出金のタイトル
+	Amount     int    // 発生した月ごとの合計の中央値。入金が正、出金が負
+	DayOfMonth int    // 最も新しい入出金の日。月末より後の日は月末に発生するとみなす
+	MonthCount int    // 発生した月数
+	key        string
+}
+
+// DetectRecurringCashFlows はmonthStartより前のhistoryMonthsか月の履歴から、毎月繰り返す入出金を検出する
+// 半数以上の月（最低2か月）で発生し、直近の月にも発生しているものを繰り返しとみなす
+// historyにはmonthStart以降の当月の入出金を含めてよく、発生日の判定にのみ使う
+func DetectRecurringCashFlows(history []*CashFlowEntry, monthStart time.Time, historyMonths int) []*RecurringCashFlow {
+	minMonths := max((historyMonths+1)/2, 2)
+	currentMonth := monthIndex(monthStart)
+
+	type group struct {
+		monthAmounts map[int]int
+		latest       *CashFlowEntry
+	}
+	groups := make(map[string]*group)
+	keys := make([]string, 0)
+	for _, entry := range history {
+		key := entry.recurringKey()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{monthAmounts: make(map[int]int)}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		if g.latest == nil || !entry.At.Before(g.latest.At) {
+			g.latest = entry
+		}
+		if month := monthIndex(entry.At); month < currentMonth {
+			g.monthAmounts[month] += entry.Amount
+		}
+	}
+
+	recurring := make([]*RecurringCashFlow, 0)
+	for _, key := range keys {
+		g := groups[key]
+		if len(g.monthAmounts) < minMonths || monthIndex(g.latest.At) < currentMonth-cashFlowRecurringRecentMonths {
+			continue
+		}
+
+		amounts := make([]int, 0, len(g.monthAmounts))
+		for _, amount := range g.monthAmounts {
+			amounts = append(amounts, amount)
+		}
+		sort.Ints(amounts)
+
+		recurring = append(recurring, &RecurringCashFlow{
+			Name:       g.latest.Name,
+			Amount:     amounts[len(amounts)/2],
+			DayOfMonth: g.latest.At.In(time.Local).Day(),
+			MonthCount: len(g.monthAmounts),
+			key:        key,
+		})
+	}
+
+	sort.SliceStable(recurring, func(i, j int) bool {
+		if recurring[i].DayOfMonth != recurring[j].DayOfMonth {
+			return recurring[i].DayOfMonth < recurring[j].DayOfMonth
+		}
+		return recurring[i].Name < recurring[j].Name
+	})
+
+	return recurring
+}
+
+// CashFlowEvent は予測に含めた入出金。Projectedがfalseの場合は登録済みの未来の日時のRecord
+type CashFlowEvent struct {
+	At        time.Time
+	Name      string
+	Amount    int
+	Projected bool
+	RecordID  *RecordID // 繰り返しから予測した入出金の場合はnil
+}
+
+// CashFlowDay は1日ごとの予測。Balanceはその日の終わりの残高
+type CashFlowDay struct {
+	Date           time.Time
+	Inflow         int
+	Outflow        int // 正の値
+	Balance        int
+	BelowThreshold bool
+	Events         []*CashFlowEvent
+}
+
+// CashFlowForecast はAssetの残高の予測
+type CashFlowForecast struct {
+	AssetID             AssetID
+	Until               time.Time
+	Threshold           int
+	CurrentBalance      int
+	LowestBalance       int
+	Recurring           []*RecurringCashFlow
+	Days                []*CashFlowDay // 今日からuntilの前日まで。untilが日の途中の場合はその日まで
+	BelowThresholdDates []time.Time
+}
+
+// NewCashFlowForecast は現在の残高に、登録済みの未来の入出金と繰り返しから予測した入出金を日ごとに加えて残高を予測する
+// 繰り返しの入出金は、当月の履歴や登録済みの未来の入出金にすでにある月には予測しない
+func NewCashFlowForecast(assetID AssetID, now, until time.Time, threshold, currentBalance int, recurring []*RecurringCashFlow, currentMonth, future []*CashFlowEntry) *CashFlowForecast {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	occurred := make(map[string]map[int]bool)
+	events := make([]*CashFlowEvent, 0, len(future))
+	for _, entries := range [][]*CashFlowEntry{currentMonth, future} {
+		for _, entry := range entries {
+			key := entry.recurringKey()
+			if occurred[key] == nil {
+				occurred[key] = make(map[int]bool)
+			}
+			occurred[key][monthIndex(entry.At)] = true
+		}
+	}
+	for _, entry := range future {
+		events = append(events, &CashFlowEvent{At: entry.At, Name: entry.Name, Amount: entry.Amount, RecordID: &entry.RecordID})
+	}
+
+	for _, r := range recurring {
+		for month := 0; ; month++ {
+			first := time.Date(today.Year(), today.Month()+time.Month(month), 1, 0, 0, 0, 0, time.Local)
+			lastDay := first.AddDate(0, 1, -1).Day()
+			date := first.AddDate(0, 0, min(r.DayOfMonth, lastDay)-1)
+			if !date.Before(until) {
+				break
+			}
+			if date.Before(today) || occurred[r.key][monthIndex(date)] {
+				continue
+			}
+			events = append(events, &CashFlowEvent{At: date, Name: r.Name, Amount: r.Amount, Projected: true})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	forecast := &CashFlowForecast{
+		AssetID:             assetID,
+		Until:               until,
+		Threshold:           threshold,
+		CurrentBalance:      currentBalance,
+		LowestBalance:       currentBalance,
+		Recurring:           recurring,
+		Days:                make([]*CashFlowDay, 0),
+		BelowThresholdDates: make([]time.Time, 0),
+	}
+
+	balance := currentBalance
+	for date := today; date.Before(until); date = date.AddDate(0, 0, 1) {
+		next := date.AddDate(0, 0, 1)
+		day := &CashFlowDay{Date: date, Events: make([]*CashFlowEvent, 0)}
+		for len(events) > 0 && events[0].At.Before(next) {
+			event := events[0]
+			events = events[1:]
+			if event.Amount > 0 {
+				day.Inflow += event.Amount
+			} else {
+				day.Outflow -= event.Amount
+			}
+			day.Events = append(day.Events, event)
+		}
+
+		balance += day.Inflow - day.Outflow
+		day.Balance = balance
+		day.BelowThreshold = balance < threshold
+		if day.BelowThreshold {
+			forecast.BelowThresholdDates = append(forecast.BelowThresholdDates, date)
+		}
+		forecast.LowestBalance = min(forecast.LowestBalance, balance)
+		forecast.Days = append(forecast.Days, day)
+	}
+
+	return forecast
+}
+
+// monthIndex は月を比較するため、ローカル時刻の年月を通し番号にする
+func monthIndex(t time.Time) int {
+	t = t.In(time.Local)
+	return t.Year()*12 + int(t.Month()) - 1
+}
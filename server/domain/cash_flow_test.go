@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCashFlowEntry(name string, payeeID *PayeeID, amount int, month time.Month, day int) *CashFlowEntry {
+	return &CashFlowEntry{
+		RecordID: NewRecordID(),
+		At:       time.Date(2026, month, day, 12, 0, 0, 0, time.Local),
+		Name:     name,
+		PayeeID:  payeeID,
+		Amount:   amount,
+	}
+}
+
+func TestDetectRecurringCashFlows(t *testing.T) {
+	rent := PayeeID("rent")
+	monthStart := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		history       []*CashFlowEntry
+		historyMonths int
+		want          []RecurringCashFlow
+	}{
+		{
+			name: "payee with different titles uses median and latest entry",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("家賃1月", &rent, -80000, time.January, 27),
+				newTestCashFlowEntry("家賃2月", &rent, -80000, time.February, 27),
+				newTestCashFlowEntry("家賃3月", &rent, -85000, time.March, 27),
+				newTestCashFlowEntry("家賃4月", &rent, -80000, time.April, 26),
+			},
+			historyMonths: 4,
+			want:          []RecurringCashFlow{{Name: "家賃4月", Amount: -80000, DayOfMonth: 26, MonthCount: 4}},
+		},
+		{
+			name: "title ignores spaces and current month is only used for the day",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("給料", nil, 300000, time.February, 25),
+				newTestCashFlowEntry("給 料", nil, 300000, time.March, 25),
+				newTestCashFlowEntry("給料", nil, 100000, time.March, 31),
+				newTestCashFlowEntry("給料 ", nil, 310000, time.April, 24),
+				newTestCashFlowEntry("給料", nil, 999999, time.May, 1),
+			},
+			historyMonths: 4,
+			want:          []RecurringCashFlow{{Name: "給料", Amount: 310000, DayOfMonth: 1, MonthCount: 3}},
+		},
+		{
+			name: "inflow and outflow are separated",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("ジム", nil, -5000, time.March, 3),
+				newTestCashFlowEntry("ジム", nil, -5000, time.April, 3),
+				newTestCashFlowEntry("ジム", nil, 5000, time.April, 10),
+			},
+			historyMonths: 4,
+			want:          []RecurringCashFlow{{Name: "ジム", Amount: -5000, DayOfMonth: 3, MonthCount: 2}},
+		},
+		{
+			name: "cancelled subscription is not recurring",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("動画配信", nil, -1490, time.January, 5),
+				newTestCashFlowEntry("動画配信", nil, -1490, time.February, 5),
+			},
+			historyMonths: 4,
+			want:          []RecurringCashFlow{},
+		},
+		{
+			name: "less than half of the months is not recurring",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("書籍", nil, -2000, time.March, 15),
+				newTestCashFlowEntry("書籍", nil, -2000, time.April, 15),
+			},
+			historyMonths: 6,
+			want:          []RecurringCashFlow{},
+		},
+		{
+			name: "sorted by day and name",
+			history: []*CashFlowEntry{
+				newTestCashFlowEntry("b", nil, -100, time.March, 10),
+				newTestCashFlowEntry("b", nil, -100, time.April, 10),
+				newTestCashFlowEntry("c", nil, -100, time.March, 5),
+				newTestCashFlowEntry("c", nil, -100, time.April, 5),
+				newTestCashFlowEntry("a", nil, -100, time.March, 10),
+				newTestCashFlowEntry("a", nil, -100, time.April, 10),
+			},
+			historyMonths: 2,
+			want: []RecurringCashFlow{
+				{Name: "c", Amount: -100, DayOfMonth: 5, MonthCount: 2},
+				{Name: "a", Amount: -100, DayOfMonth: 10, MonthCount: 2},
+				{Name: "b", Amount: -100, DayOfMonth: 10, MonthCount: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectRecurringCashFlows(tt.history, monthStart, tt.historyMonths)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(DetectRecurringCashFlows()) = %d, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				r := *got[i]
+				r.key = ""
+				if r != want {
+					t.Errorf("DetectRecurringCashFlows()[%d] = %+v, want %+v", i, r, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCashFlowForecast(t *testing.T) {
+	rent := PayeeID("rent")
+	now := time.Date(2026, time.May, 10, 15, 0, 0, 0, time.Local)
+	until := time.Date(2026, time.July, 1, 0, 0, 0, 0, time.Local)
+
+	salary := newTestCashFlowEntry("給料", nil, 300000, time.April, 25)
+	rentPaid := newTestCashFlowEntry("家賃", &rent, -80000, time.May, 2)
+	recurring := []*RecurringCashFlow{
+		{Name: "給料", Amount: 300000, DayOfMonth: 25, MonthCount: 3, key: salary.recurringKey()},
+		// 6月は30日までのため月末に予測する
+		{Name: "家賃", Amount: -80000, DayOfMonth: 31, MonthCount: 3, key: rentPaid.recurringKey()},
+	}
+	// 家賃は当月支払い済みのため5月には予測しない
+	currentMonth := []*CashFlowEntry{rentPaid}
+	// 6月の給料は登録済みのため予測しない
+	future := []*CashFlowEntry{
+		newTestCashFlowEntry("家電", nil, -15000, time.May, 20),
+		newTestCashFlowEntry("給 料", nil, 310000, time.June, 24),
+	}
+
+	forecast := NewCashFlowForecast(testAssetA, now, until, 50000, 60000, recurring, currentMonth, future)
+
+	if len(forecast.Days) != 52 {
+		t.Fatalf("len(Days) = %d, want 52", len(forecast.Days))
+	}
+	if first := forecast.Days[0].Date; !first.Equal(time.Date(2026, time.May, 10, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Days[0].Date = %s, want 2026-05-10", first)
+	}
+
+	tests := []struct {
+		date           time.Time
+		inflow         int
+		outflow        int
+		balance        int
+		belowThreshold bool
+		projected      []bool
+	}{
+		{time.Date(2026, time.May, 19, 0, 0, 0, 0, time.Local), 0, 0, 60000, false, nil},
+		{time.Date(2026, time.May, 20, 0, 0, 0, 0, time.Local), 0, 15000, 45000, true, []bool{false}},
+		{time.Date(2026, time.May, 25, 0, 0, 0, 0, time.Local), 300000, 0, 345000, false, []bool{true}},
+		{time.Date(2026, time.May, 31, 0, 0, 0, 0, time.Local), 0, 0, 345000, false, nil},
+		{time.Date(2026, time.June, 24, 0, 0, 0, 0, time.Local), 310000, 0, 655000, false, []bool{false}},
+		{time.Date(2026, time.June, 25, 0, 0, 0, 0, time.Local), 0, 0, 655000, false, nil},
+		{time.Date(2026, time.June, 30, 0, 0, 0, 0, time.Local), 0, 80000, 575000, false, []bool{true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date.Format(time.DateOnly), func(t *testing.T) {
+			var day *CashFlowDay
+			for _, d := range forecast.Days {
+				if d.Date.Equal(tt.date) {
+					day = d
+				}
+			}
+			if day == nil {
+				t.Fatalf("day %s not found", tt.date.Format(time.DateOnly))
+			}
+
+			if day.Inflow != tt.inflow || day.Outflow != tt.outflow || day.Balance != tt.balance || day.BelowThreshold != tt.belowThreshold {
+				t.Errorf("day = {Inflow:%d Outflow:%d Balance:%d BelowThreshold:%t}, want {Inflow:%d Outflow:%d Balance:%d BelowThreshold:%t}",
+					day.Inflow, day.Outflow, day.Balance, day.BelowThreshold, tt.inflow, tt.outflow, tt.balance, tt.belowThreshold)
+			}
+			if len(day.Events) != len(tt.projected) {
+				t.Fatalf("len(Events) = %d, want %d", len(day.Events), len(tt.projected))
+			}
+			for i, event := range day.Events {
+				if event.Projected != tt.projected[i] {
+					t.Errorf("Events[%d].Projected = %t, want %t", i, event.Projected, tt.projected[i])
+				}
+				if event.Projected != (event.RecordID == nil) {
+					t.Errorf("Events[%d].RecordID must be nil only for projected events", i)
+				}
+			}
+		})
+	}
+
+	if forecast.LowestBalance != 45000 {
+		t.Errorf("LowestBalance = %d, want 45000", forecast.LowestBalance)
+	}
+	if len(forecast.BelowThresholdDates) != 5 {
+		t.Errorf("len(BelowThresholdDates) = %d, want 5", len(forecast.BelowThresholdDates))
+	}
+}
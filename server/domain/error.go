@@ -5,9 +5,34 @@ import (
 )
 
 var (
-	ErrEntityNotFound      = xerrors.New("entity not found")
-	ErrInvalidPageParam    = xerrors.New("page param invalid")
-	ErrInvalidRecordAmount = xerrors.New("invalid record amount")
-	ErrAssetChangeNotFound = xerrors.New("asset change not found")
-	ErrUnauthorized        = xerrors.New("unauthorized")
+	ErrEntityNotFound           = xerrors.New("entity not found")
+	ErrInvalidPageParam         = xerrors.New("page param invalid")
+	ErrInvalidRecordAmount      = xerrors.New("invalid record amount")
+	ErrAssetChangeNotFound      = xerrors.New("asset change not found")
+	ErrUnauthorized             = xerrors.New("unauthorized")
+	ErrEmptySearchQuery         = xerrors.New("empty search query")
+	ErrInvalidRecordCategory    = xerrors.New("invalid record category")
+	ErrInvalidAssetCategory     = xerrors.New("invalid asset category")
+	ErrAssetCategoryHasChildren = xerrors.New("asset category has children")
+	ErrAssetCategoryNotEmpty    = xerrors.New("asset category has assets")
+	ErrInvalidRecordBulkPatch   = xerrors.New("invalid record bulk patch")
+	ErrInvalidRecordPatch       = xerrors.New("invalid record patch")
+	ErrConflict                 = xerrors.New("entity was updated by another request")
+	ErrInvalidRecordMerge       = xerrors.New("invalid record merge")
+	ErrInvalidDateRange         = xerrors.New("invalid date range")
+	ErrInvalidPayee             = xerrors.New("invalid payee")
+	ErrPayeeNameConflict        = xerrors.New("payee name is already used")
+	ErrInvalidAttachment        = xerrors.New("invalid attachment")
+	ErrInvalidRecordItems       = xerrors.New("invalid record items")
+	ErrInvalidTax               = xerrors.New("invalid tax")
+	ErrInvalidBusinessRatio     = xerrors.New("invalid business ratio")
+	ErrInvalidGoal              = xerrors.New("invalid goal")
+	ErrAttachmentQuotaExceeded  = xerrors.New("attachment quota exceeded")
+	ErrInvalidIdempotencyKey    = xerrors.New("invalid idempotency key")
+	ErrIdempotencyKeyExists     = xerrors.New("idempotency key already exists")
+	ErrInvalidTagColor          = xerrors.New("invalid tag color")
+	ErrInvalidTag               = xerrors.New("invalid tag")
+	ErrTagNameConflict          = xerrors.New("tag name is already used")
+	ErrAssetHasHistory          = xerrors.New("asset has history")
+	ErrAssetHasTransfer         = xerrors.New("asset has transfer records")
 )
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const GoalIDSuffix = "Goal"
+
+type GoalID string
+
+func NewGoalID() GoalID {
+	return GoalID(NewUUIDv4(GoalIDSuffix))
+}
+
+// Goal は貯蓄の目標。紐付けたAssetの残高の合計で進捗を測る
+type Goal struct {
+	ID           GoalID
+	UserID       UserID
+	Name         string
+	TargetAmount int
+	TargetDate   time.Time
+	AssetIDs     []AssetID // goal_assetテーブルから取得する
+	CreatedAt    time.Time // 進捗の計画はこの時点の残高から始める
+	UpdatedAt    time.Time
+}
+
+func NewGoal(userID UserID, name string, targetAmount int, targetDate time.Time, assetIDs []AssetID) (*Goal, error) {
+	goal := &Goal{
+		ID:        NewGoalID(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err := goal.Update(name, targetAmount, targetDate, assetIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+// Update は目標の内容と紐付けるAssetを置き換える。重複したAssetは取り除く
+func (g *Goal) Update(name string, targetAmount int, targetDate time.Time, assetIDs []AssetID) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return xerrors.Errorf("goal name is required: %w", ErrInvalidGoal)
+	}
+	if targetAmount <= 0 {
+		return xerrors.Errorf("target amount must be positive: %w", ErrInvalidGoal)
+	}
+	if targetDate.IsZero() {
+		return xerrors.Errorf("target date is required: %w", ErrInvalidGoal)
+	}
+
+	seen := make(map[AssetID]struct{}, len(assetIDs))
+	uniqueAssetIDs := make([]AssetID, 0, len(assetIDs))
+	for _, id := range assetIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueAssetIDs = append(uniqueAssetIDs, id)
+	}
+	if len(uniqueAssetIDs) == 0 {
+		return xerrors.Errorf("goal must be linked to at least one asset: %w", ErrInvalidGoal)
+	}
+
+	g.Name = name
+	g.TargetAmount = targetAmount
+	g.TargetDate = targetDate
+	g.AssetIDs = uniqueAssetIDs
+
+	return nil
+}
+
+type Goals []*Goal
+
+// GoalStatus は目標の達成状況
+type GoalStatus string
+
+const (
+	GoalStatusAchieved GoalStatus = "ACHIEVED" // 残高が目標額に達している
+	GoalStatusOnTrack  GoalStatus = "ON_TRACK" // 計画どおりのペースで貯まっている
+	GoalStatusBehind   GoalStatus = "BEHIND"   // 計画より遅れている、または期限を過ぎた
+)
+
+// GoalProgress は目標の進捗
+type GoalProgress struct {
+	CurrentAmount int // 紐付けたAssetの現在の残高の合計
+	StartAmount   int // 目標を作成した時点の残高の合計
+	// ExpectedAmount は作成時点の残高から期限の目標額まで毎月同じ額を積み立てた場合に、現在あるべき残高
+	ExpectedAmount  int
+	RemainingAmount int // 目標額までの不足額。達成済みの場合は0
+	MonthsLeft      int // 期限までの残り月数（端数は切り上げ）。期限を過ぎた場合は0
+	// RequiredMonthlyContribution は期限までに目標額に達するために毎月必要な積立額。期限を過ぎた場合は不足額の全額
+	RequiredMonthlyContribution int
+	Status                      GoalStatus
+}
+
+// NewGoalProgress は作成時点と現在の残高から、nowにおける目標の進捗を計算する
+func NewGoalProgress(goal *Goal, startAmount, currentAmount int, now time.Time) *GoalProgress {
+	progress := &GoalProgress{
+		CurrentAmount:   currentAmount,
+		StartAmount:     startAmount,
+		ExpectedAmount:  goal.expectedAmount(startAmount, now),
+		RemainingAmount: max(goal.TargetAmount-currentAmount, 0),
+		MonthsLeft:      monthsUntil(now, goal.TargetDate),
+	}
+
+	progress.RequiredMonthlyContribution = progress.RemainingAmount
+	if progress.MonthsLeft > 0 {
+		progress.RequiredMonthlyContribution = (progress.RemainingAmount + progress.MonthsLeft - 1) / progress.MonthsLeft
+	}
+
+	switch {
+	case progress.RemainingAmount == 0:
+		progress.Status = GoalStatusAchieved
+	case progress.MonthsLeft > 0 && currentAmount >= progress.ExpectedAmount:
+		progress.Status = GoalStatusOnTrack
+	default:
+		progress.Status = GoalStatusBehind
+	}
+
+	return progress
+}
+
+// expectedAmount は作成時点から期限まで直線的に残高が増えた場合の、nowにおける残高を返す
+func (g *Goal) expectedAmount(startAmount int, now time.Time) int {
+	if startAmount >= g.TargetAmount || !now.Before(g.TargetDate) {
+		return g.TargetAmount
+	}
+	if !now.After(g.CreatedAt) || !g.TargetDate.After(g.CreatedAt) {
+		return startAmount
+	}
+
+	elapsed := now.Sub(g.CreatedAt).Seconds()
+	total := g.TargetDate.Sub(g.CreatedAt).Seconds()
+	return startAmount + int(float64(g.TargetAmount-startAmount)*elapsed/total)
+}
+
+// monthsUntil はfromからtoまでの月数を切り上げて返す。toがfrom以前の場合は0
+func monthsUntil(from, to time.Time) int {
+	if !from.Before(to) {
+		return 0
+	}
+
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if from.AddDate(0, months, 0).Before(to) {
+		months++
+	}
+
+	return months
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthsUntil(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want int
+	}{
+		{"same day", date(2026, 1, 10), date(2026, 1, 10), 0},
+		{"past", date(2026, 3, 10), date(2026, 1, 10), 0},
+		{"one day", date(2026, 1, 10), date(2026, 1, 11), 1},
+		{"exact month", date(2026, 1, 10), date(2026, 2, 10), 1},
+		{"month and a day", date(2026, 1, 10), date(2026, 2, 11), 2},
+		{"less than a month across months", date(2026, 1, 15), date(2026, 2, 10), 1},
+		{"across years", date(2025, 11, 10), date(2026, 2, 10), 3},
+		{"month end", date(2026, 1, 31), date(2026, 3, 1), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := monthsUntil(tt.from, tt.to)
+			if got != tt.want {
+				t.Errorf("monthsUntil(%s, %s) = %d, want %d", tt.from.Format(time.DateOnly), tt.to.Format(time.DateOnly), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGoalProgress(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	goal := &Goal{
+		TargetAmount: 1000,
+		TargetDate:   createdAt.AddDate(0, 0, 10),
+		CreatedAt:    createdAt,
+	}
+
+	tests := []struct {
+		name          string
+		startAmount   int
+		currentAmount int
+		now           time.Time
+		want          GoalProgress
+	}{
+		{
+			name:          "on track at half time",
+			startAmount:   0,
+			currentAmount: 500,
+			now:           createdAt.AddDate(0, 0, 5),
+			want: GoalProgress{
+				CurrentAmount: 500, StartAmount: 0, ExpectedAmount: 500, RemainingAmount: 500,
+				MonthsLeft: 1, RequiredMonthlyContribution: 500, Status: GoalStatusOnTrack,
+			},
+		},
+		{
+			name:          "behind at half time",
+			startAmount:   200,
+			currentAmount: 500,
+			now:           createdAt.AddDate(0, 0, 5),
+			want: GoalProgress{
+				CurrentAmount: 500, StartAmount: 200, ExpectedAmount: 600, RemainingAmount: 500,
+				MonthsLeft: 1, RequiredMonthlyContribution: 500, Status: GoalStatusBehind,
+			},
+		},
+		{
+			name:          "before creation",
+			startAmount:   300,
+			currentAmount: 300,
+			now:           createdAt.AddDate(0, 0, -1),
+			want: GoalProgress{
+				CurrentAmount: 300, StartAmount: 300, ExpectedAmount: 300, RemainingAmount: 700,
+				MonthsLeft: 1, RequiredMonthlyContribution: 700, Status: GoalStatusOnTrack,
+			},
+		},
+		{
+			name:          "achieved",
+			startAmount:   0,
+			currentAmount: 1200,
+			now:           createdAt.AddDate(0, 0, 5),
+			want: GoalProgress{
+				CurrentAmount: 1200, StartAmount: 0, ExpectedAmount: 500, RemainingAmount: 0,
+				MonthsLeft: 1, RequiredMonthlyContribution: 0, Status: GoalStatusAchieved,
+			},
+		},
+		{
+			name:          "overdue",
+			startAmount:   0,
+			currentAmount: 900,
+			now:           createdAt.AddDate(0, 0, 11),
+			want: GoalProgress{
+				CurrentAmount: 900, StartAmount: 0, ExpectedAmount: 1000, RemainingAmount: 100,
+				MonthsLeft: 0, RequiredMonthlyContribution: 100, Status: GoalStatusBehind,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewGoalProgress(goal, tt.startAmount, tt.currentAmount, tt.now)
+			if *got != tt.want {
+				t.Errorf("NewGoalProgress() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGoalProgressRequiredMonthlyContribution(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	goal := &Goal{
+		TargetAmount: 100000,
+		TargetDate:   createdAt.AddDate(0, 3, 0),
+		CreatedAt:    createdAt,
+	}
+
+	// 不足額を残り月数で割った端数は切り上げる
+	got := NewGoalProgress(goal, 0, 0, createdAt)
+	if got.MonthsLeft != 3 || got.RequiredMonthlyContribution != 33334 {
+		t.Errorf("MonthsLeft, RequiredMonthlyContribution = %d, %d, want 3, 33334", got.MonthsLeft, got.RequiredMonthlyContribution)
+	}
+}
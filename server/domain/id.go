@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"kakeibo-web-server/lib/id"
+	"strings"
 )
 
 const (
@@ -16,3 +17,14 @@ type ID string
 func NewUUIDv4(suffix string) ID {
 	return ID(fmt.Sprintf("%s%s%s", id.NewUUIDv4(), IDSeparator, suffix))
 }
+
+// Suffix はNewUUIDv4で付与されたエンティティ種別を返す
+// 種別が付与されていないIDの場合は空文字を返す
+func (i ID) Suffix() string {
+	index := strings.LastIndex(string(i), IDSeparator)
+	if index < 0 {
+		return ""
+	}
+
+	return string(i)[index+len(IDSeparator):]
+}
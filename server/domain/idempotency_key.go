@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// IdempotencyKeyRetention は冪等キーを保持する期間。これより古いキーは再利用できる
+const IdempotencyKeyRetention = 24 * time.Hour
+
+const idempotencyKeyMaxLength = 255
+
+// IdempotentOperation は冪等キーを指定できる作成操作の種類
+type IdempotentOperation string
+
+const (
+	IdempotentOperationCreateIncomeRecord   IdempotentOperation = "CREATE_INCOME_RECORD"
+	IdempotentOperationCreateExpenseRecord  IdempotentOperation = "CREATE_EXPENSE_RECORD"
+	IdempotentOperationCreateTransferRecord IdempotentOperation = "CREATE_TRANSFER_RECORD"
+	IdempotentOperationCreateAsset          IdempotentOperation = "CREATE_ASSET"
+	IdempotentOperationCreateAssetCategory  IdempotentOperation = "CREATE_ASSET_CATEGORY"
+	IdempotentOperationCreateTag            IdempotentOperation = "CREATE_TAG"
+	IdempotentOperationCreateRecordCategory IdempotentOperation = "CREATE_RECORD_CATEGORY"
+	IdempotentOperationCreatePayee          IdempotentOperation = "CREATE_PAYEE"
+	IdempotentOperationCreateGoal           IdempotentOperation = "CREATE_GOAL"
+)
+
+// IdempotencyKey は作成操作の再送を検出するため、クライアントが指定したキーと作成したリソースを紐づける
+type IdempotencyKey struct {
+	UserID      UserID
+	Key         string
+	Operation   IdempotentOperation
+	ResourceID  string
+	RequestHash string // 最初の操作の入力のSHA-256。再送時に同じ入力かを判定する
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewIdempotencyKey は冪等キーを作成する。inputは操作の入力で、JSONにしたハッシュを保存する
+func NewIdempotencyKey(userID UserID, key string, operation IdempotentOperation, input any, resourceID string) (*IdempotencyKey, error) {
+	if key == "" || len(key) > idempotencyKeyMaxLength {
+		return nil, xerrors.Errorf("idempotency key must be 1 to %d bytes: %w", idempotencyKeyMaxLength, ErrInvalidIdempotencyKey)
+	}
+	requestHash, err := newIdempotencyRequestHash(input)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Operation:   operation,
+		ResourceID:  resourceID,
+		RequestHash: requestHash,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+func newIdempotencyRequestHash(input any) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", xerrors.Errorf("failed to marshal idempotent request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ValidateReplay は再送された操作が最初の操作と同じ種類・同じ入力かを検証する
+// 別の操作に同じキーを使った場合はErrInvalidIdempotencyKey、入力が異なる場合はErrIdempotencyKeyExistsを返す
+func (k *IdempotencyKey) ValidateReplay(replay *IdempotencyKey) error {
+	if k.Operation != replay.Operation {
+		return xerrors.Errorf("idempotency key was used for %s, not %s: %w", k.Operation, replay.Operation, ErrInvalidIdempotencyKey)
+	}
+	if k.RequestHash != replay.RequestHash {
+		return xerrors.Errorf("idempotency key %s was used with a different input: %w", k.Key, ErrIdempotencyKeyExists)
+	}
+
+	return nil
+}
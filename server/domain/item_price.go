@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ItemPriceHistoryMaxRange はitemPriceHistoriesで一度に探せる期間の上限
+	ItemPriceHistoryMaxRange = 3 * 366 * 24 * time.Hour
+
+	// itemPriceMinSimilarity は検索語を含まない品名を一致とみなす類似度の下限
+	itemPriceMinSimilarity = 0.6
+)
+
+// ItemPrice は支出の明細、または明細のない支出のRecordから読み取った品物の価格
+// 明細のないRecordはタイトルを品名、金額を数量1の単価とみなす
+type ItemPrice struct {
+	RecordID  RecordID
+	PayeeID   *PayeeID
+	At        time.Time
+	Name      string
+	Quantity  int
+	UnitPrice int
+}
+
+// ItemPayeePriceStat は支払先ごとの単価の集計
+type ItemPayeePriceStat struct {
+	PayeeID       *PayeeID // nilの場合は支払先が設定されていないRecordの集計
+	MinUnitPrice  int
+	MaxUnitPrice  int
+	LastUnitPrice int
+	LastSeenAt    time.Time
+	Count         int
+}
+
+// ItemPriceHistory は正規化した品名が同じ価格の推移
+type ItemPriceHistory struct {
+	Name           string  // 最後に記録したときの表記
+	NormalizedName string  // 全角・半角、大文字・小文字、カタカナ・ひらがな、空白の違いを無視した品名
+	Similarity     float64 // 検索語との類似度。品名が検索語を含む場合は1
+	Prices         []*ItemPrice
+	Payees         []*ItemPayeePriceStat
+}
+
+// ValidateItemPriceQuery は品名の検索語が正規化した後に空でないかを検証する。空白だけの検索語はすべての品名に一致してしまう
+func ValidateItemPriceQuery(query string) error {
+	if normalizeForMatch(query) == "" {
+		return xerrors.Errorf("query %q has no searchable characters: %w", query, ErrEmptySearchQuery)
+	}
+
+	return nil
+}
+
+// ItemPriceCandidateChars は品名の候補をDBで絞り込むための文字を返す
+// 類似度が下限以上の品名は検索語と少なくとも1文字を共有するため、いずれかの文字を含む品名だけを読み込めばよい
+// 正規化でひらがなにそろえた文字はカタカナの表記も返す。全角・半角、大文字・小文字の違いはDBの照合順序で吸収する
+func ItemPriceCandidateChars(query string) []string {
+	chars := make([]string, 0)
+	seen := make(map[rune]struct{})
+	for _, r := range normalizeForMatch(query) {
+		variants := []rune{r}
+		if r >= 'ぁ' && r <= 'ゖ' {
+			variants = append(variants, r+0x60)
+		}
+		for _, v := range variants {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			chars = append(chars, string(v))
+		}
+	}
+
+	return chars
+}
+
+// NewItemPriceHistories はqueryに一致する品名の価格を品名ごとにまとめ、類似度の高い順に返す
+// 品名が検索語を含むか、編集距離による類似度が一定以上の場合に一致とみなす
+func NewItemPriceHistories(query string, prices []*ItemPrice) []*ItemPriceHistory {
+	normalizedQuery := normalizeForMatch(query)
+
+	historyMap := make(map[string]*ItemPriceHistory)
+	histories := make([]*ItemPriceHistory, 0)
+	for _, price := range prices {
+		normalizedName := normalizeForMatch(price.Name)
+		if normalizedName == "" {
+			continue
+		}
+
+		history, ok := historyMap[normalizedName]
+		if !ok {
+			similarity := 1.0
+			if !strings.Contains(normalizedName, normalizedQuery) {
+				similarity = TitleSimilarity(normalizedQuery, normalizedName)
+			}
+			history = &ItemPriceHistory{
+				NormalizedName: normalizedName,
+				Similarity:     similarity,
+				Prices:         make([]*ItemPrice, 0),
+			}
+			historyMap[normalizedName] = history
+			if similarity >= itemPriceMinSimilarity {
+				histories = append(histories, history)
+			}
+		}
+		history.Prices = append(history.Prices, price)
+	}
+
+	for _, history := range histories {
+		sort.SliceStable(history.Prices, func(i, j int) bool {
+			return history.Prices[i].At.Before(history.Prices[j].At)
+		})
+		history.Name = history.Prices[len(history.Prices)-1].Name
+		history.Payees = newItemPayeePriceStats(history.Prices)
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		if histories[i].Similarity != histories[j].Similarity {
+			return histories[i].Similarity > histories[j].Similarity
+		}
+		return len(histories[i].Prices) > len(histories[j].Prices)
+	})
+
+	return histories
+}
+
+// newItemPayeePriceStats はAtの古い順に並んだ価格を支払先ごとに集計し、最後に記録した順に返す
+func newItemPayeePriceStats(prices []*ItemPrice) []*ItemPayeePriceStat {
+	statMap := make(map[PayeeID]*ItemPayeePriceStat)
+	var noPayeeStat *ItemPayeePriceStat
+	stats := make([]*ItemPayeePriceStat, 0)
+
+	for _, price := range prices {
+		var stat *ItemPayeePriceStat
+		if price.PayeeID == nil {
+			stat = noPayeeStat
+		} else {
+			stat = statMap[*price.PayeeID]
+		}
+
+		if stat == nil {
+			stat = &ItemPayeePriceStat{
+				PayeeID:      price.PayeeID,
+				MinUnitPrice: price.UnitPrice,
+				MaxUnitPrice: price.UnitPrice,
+			}
+			if price.PayeeID == nil {
+				noPayeeStat = stat
+			} else {
+				statMap[*price.PayeeID] = stat
+			}
+			stats = append(stats, stat)
+		}
+
+		stat.MinUnitPrice = min(stat.MinUnitPrice, price.UnitPrice)
+		stat.MaxUnitPrice = max(stat.MaxUnitPrice, price.UnitPrice)
+		stat.LastUnitPrice = price.UnitPrice
+		stat.LastSeenAt = price.At
+		stat.Count++
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].LastSeenAt.After(stats[j].LastSeenAt)
+	})
+
+	return stats
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestItemPriceCandidateChars(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"ascii is lowercased", "Ab a", []string{"a", "b"}},
+		{"katakana is also returned for hiragana", "ﾐﾙｸ", []string{"み", "ミ", "る", "ル", "く", "ク"}},
+		{"kanji", "牛乳 牛", []string{"牛", "乳"}},
+		{"spaces only", " 　", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ItemPriceCandidateChars(tt.query)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ItemPriceCandidateChars(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
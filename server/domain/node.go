@@ -0,0 +1,16 @@
+package domain
+
+// Node はRelayのNodeインターフェースを実装するエンティティを表す
+// IDの末尾に付与された種別（IDSuffix）からエンティティを特定できる
+type Node interface {
+	IsNode()
+}
+
+func (Asset) IsNode()          {}
+func (AssetCategory) IsNode()  {}
+func (Tag) IsNode()            {}
+func (Record) IsNode()         {}
+func (RecordCategory) IsNode() {}
+func (Payee) IsNode()          {}
+func (Goal) IsNode()           {}
+func (User) IsNode()           {}
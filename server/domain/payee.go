@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	PayeeIDSuffix = "Payee"
+
+	// payeeMatchMinLength はタイトルの一部に含まれる名前で照合する場合の最小文字数。短すぎる名前は誤って一致しやすい
+	payeeMatchMinLength = 2
+)
+
+type PayeeID string
+
+func NewPayeeID() PayeeID {
+	return PayeeID(NewUUIDv4(PayeeIDSuffix))
+}
+
+// Payee は支払先（店舗など）。表記ゆれを吸収するため、名前のほかに別名を持つ
+type Payee struct {
+	ID        PayeeID
+	UserID    UserID
+	Name      string
+	Aliases   []string // payee_aliasテーブルから取得する
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewPayee(userID UserID, name string, aliases []string) (*Payee, error) {
+	payee := &Payee{
+		ID:        NewPayeeID(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err := payee.Rename(name, aliases)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// Rename は名前と別名を置き換える。名前と同じ表記や重複した別名は取り除く
+func (p *Payee) Rename(name string, aliases []string) error {
+	name = strings.TrimSpace(name)
+	if NormalizePayeeName(name) == "" {
+		return xerrors.Errorf("payee name is required: %w", ErrInvalidPayee)
+	}
+
+	seen := map[string]struct{}{NormalizePayeeName(name): {}}
+	uniqueAliases := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := NormalizePayeeName(alias)
+		if key == "" {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		uniqueAliases = append(uniqueAliases, alias)
+	}
+
+	p.Name = name
+	p.Aliases = uniqueAliases
+
+	return nil
+}
+
+// MatchKeys は照合に使う、正規化した名前と別名を返す
+func (p *Payee) MatchKeys() []string {
+	keys := make([]string, 0, len(p.Aliases)+1)
+	keys = append(keys, NormalizePayeeName(p.Name))
+	for _, alias := range p.Aliases {
+		keys = append(keys, NormalizePayeeName(alias))
+	}
+	return keys
+}
+
+// NormalizePayeeName は全角・半角、大文字・小文字、カタカナ・ひらがな、空白の違いを無視して照合できるように正規化する
+func NormalizePayeeName(name string) string {
+	return normalizeForMatch(name)
+}
+
+type Payees []*Payee
+
+// ValidateNames はtargetの名前・別名が他のPayeeの名前・別名と重複しないかを検証する
+func (payees Payees) ValidateNames(target *Payee) error {
+	keys := make(map[string]struct{})
+	for _, key := range target.MatchKeys() {
+		keys[key] = struct{}{}
+	}
+
+	for _, payee := range payees {
+		if payee.ID == target.ID {
+			continue
+		}
+		for _, key := range payee.MatchKeys() {
+			if _, ok := keys[key]; ok {
+				return xerrors.Errorf("%q is already used by payee %s: %w", key, payee.Name, ErrPayeeNameConflict)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Match はRecordのタイトルに対応するPayeeを返す。対応するPayeeがない場合はnilを返す
+// 名前・別名とタイトルが一致するものを優先し、なければタイトルに含まれる名前・別名のうち最も長いものを選ぶ
+func (payees Payees) Match(title string) *Payee {
+	normalizedTitle := NormalizePayeeName(title)
+	if normalizedTitle == "" {
+		return nil
+	}
+
+	var matched *Payee
+	matchedLength := 0
+	for _, payee := range payees {
+		for _, key := range payee.MatchKeys() {
+			if key == normalizedTitle {
+				return payee
+			}
+
+			length := utf8.RuneCountInString(key)
+			if length >= payeeMatchMinLength && length > matchedLength && strings.Contains(normalizedTitle, key) {
+				matched = payee
+				matchedLength = length
+			}
+		}
+	}
+
+	return matched
+}
+
+// PayeeAmount はPayeeごとに集計した支出の金額。PayeeIDがnilの場合はPayeeが設定されていないRecordの集計
+type PayeeAmount struct {
+	PayeeID     *PayeeID
+	Amount      int
+	RecordCount int
+}
+
+// PayeeSpending はPayeeごとの支出の集計結果
+type PayeeSpending struct {
+	Payee       *Payee // nilの場合はPayeeが設定されていないRecordの集計
+	Amount      int
+	RecordCount int
+}
@@ -1,11 +1,14 @@
 package domain
 
 import (
+	"strconv"
 	"time"
+
+	"golang.org/x/xerrors"
 )
 
 const (
-	RecordIDSuffix = "User"
+	RecordIDSuffix = "Record"
 )
 
 type RecordID string
@@ -23,14 +26,21 @@ const (
 )
 
 type Record struct {
-	ID          RecordID
-	UserID      UserID
-	RecordType  RecordType
-	Title       string
-	Description string
-	At          time.Time // 入出金が発生した日時（ユーザー指定）
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID            RecordID
+	UserID        UserID
+	RecordType    RecordType
+	Title         string
+	Description   string
+	At            time.Time         // 入出金が発生した日時（ユーザー指定）
+	CategoryID    *RecordCategoryID // 振替の場合は常にnil
+	PayeeID       *PayeeID          // 振替の場合は常にnil
+	TaxRate       *TaxRate          // nilの場合は課税対象外。振替の場合は常にnil
+	TaxMode       TaxMode           // 明細の単価が税込か税抜か
+	TaxRounding   TaxRounding       // 税率・明細を設定した時点のユーザーの端数処理。後からユーザーの設定を変えても変わらない
+	BusinessRatio *int              // 事業に使った割合（%）。nilの場合はTag・Assetの既定値を使う。支出以外は常にnil
+	Version       int               // 更新のたびに増える。楽観的排他制御に使う
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func newRecord(userID UserID, recordType RecordType, title, description string, at time.Time) *Record {
@@ -41,6 +51,9 @@ func newRecord(userID UserID, recordType RecordType, title, description string,
 		Title:       title,
 		Description: description,
 		At:          at,
+		TaxMode:     TaxModeInclusive,
+		TaxRounding: TaxRoundingFloor,
+		Version:     1,
 	}
 }
 
@@ -77,8 +90,70 @@ func NewRecordTransferWithAssetChanges(userID UserID, title string, description
 	return record, fromAssetChange, toAssetChange, nil
 }
 
+// SetPayee はRecordの支払先を設定する。nilの場合は支払先なしにする。振替には設定できない
+func (r *Record) SetPayee(payee *Payee) error {
+	if payee == nil {
+		r.PayeeID = nil
+		return nil
+	}
+
+	if r.RecordType == RecordTypeTransfer {
+		return xerrors.Errorf("transfer record cannot have payee: %w", ErrInvalidPayee)
+	}
+
+	r.PayeeID = &payee.ID
+
+	return nil
+}
+
+// SetCategory はRecordの分類を設定する。nilの場合は未分類にする
+func (r *Record) SetCategory(category *RecordCategory) error {
+	if category == nil {
+		r.CategoryID = nil
+		return nil
+	}
+
+	err := category.ValidateRecordType(r.RecordType)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	r.CategoryID = &category.ID
+
+	return nil
+}
+
 type Records []*Record
 
+func (records Records) IDs() []RecordID {
+	ids := make([]RecordID, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+// RecordWithAmount はソートやカーソルに使うためにRecordの金額（AssetChangeの絶対値）を持つ
+type RecordWithAmount struct {
+	Record
+	Amount int
+}
+
+func (r RecordWithAmount) CursorValue(sortKey RecordSortKey) (string, error) {
+	switch sortKey {
+	case RecordSortKeyAt:
+		return r.At.Format("2006-01-02 15:04:05"), nil
+	case RecordSortKeyCreatedAt:
+		return r.CreatedAt.Format("2006-01-02 15:04:05"), nil
+	case RecordSortKeyTitle:
+		return r.Title, nil
+	case RecordSortKeyAmount:
+		return strconv.Itoa(r.Amount), nil
+	default:
+		return "", xerrors.Errorf("unsupported sort key for record %s: %w", sortKey, ErrInvalidPageParam)
+	}
+}
+
 func (records Records) OldestRecord(isReverse bool) (*Record, error) {
 	if len(records) == 0 {
 		return nil, ErrEntityNotFound
@@ -89,3 +164,85 @@ func (records Records) OldestRecord(isReverse bool) (*Record, error) {
 	}
 	return records[0], nil
 }
+
+// RecordCondition はRecord一覧の絞り込み条件（ページネーションを除く）
+type RecordCondition struct {
+	AtFrom      *time.Time      // この日時以降のRecordに絞り込む
+	AtTo        *time.Time      // この日時より前のRecordに絞り込む
+	AmountMin   *int            // 金額（AssetChangeの絶対値）の下限（含む）
+	AmountMax   *int            // 金額（AssetChangeの絶対値）の上限（含む）
+	Tags        []*TagCondition // すべての条件を満たすRecordに絞り込む
+	AssetIDs    []AssetID
+	CategoryIDs []RecordCategoryID // 指定した分類とその子孫の分類のRecordに絞り込む
+	PayeeIDs    []PayeeID
+	RecordTypes []RecordType
+	Text        string // タイトルまたは説明に含まれる文字列
+}
+
+// AddTagCondition はTagによる絞り込み条件を追加する。namesが空の場合は何もしない
+func (c *RecordCondition) AddTagCondition(names []string, mode TagMatchMode) *RecordCondition {
+	if len(names) == 0 {
+		return c
+	}
+
+	c.Tags = append(c.Tags, &TagCondition{
+		Names: NormalizeTagNames(names),
+		Mode:  mode,
+	})
+
+	return c
+}
+
+func NewRecordConditionFromFilter(filter *RecordFilter) *RecordCondition {
+	cond := &RecordCondition{}
+	if filter == nil {
+		return cond
+	}
+
+	cond.AtFrom = filter.From
+	cond.AtTo = filter.To
+	cond.AmountMin = filter.AmountMin
+	cond.AmountMax = filter.AmountMax
+	cond.RecordTypes = filter.RecordTypes
+
+	cond.AddTagCondition(filter.TagNames, TagMatchModeAny)
+	for _, tagFilter := range filter.Tags {
+		cond.AddTagCondition(tagFilter.Names, tagFilter.Mode)
+	}
+
+	for _, assetID := range filter.AssetIDs {
+		cond.AssetIDs = append(cond.AssetIDs, AssetID(assetID))
+	}
+
+	for _, categoryID := range filter.CategoryIDs {
+		cond.CategoryIDs = append(cond.CategoryIDs, RecordCategoryID(categoryID))
+	}
+
+	for _, payeeID := range filter.PayeeIDs {
+		cond.PayeeIDs = append(cond.PayeeIDs, PayeeID(payeeID))
+	}
+
+	if filter.Text != nil {
+		cond.Text = *filter.Text
+	}
+
+	return cond
+}
+
+// InMonth は絞り込む期間を指定した年月の1か月間に置き換える
+func (c *RecordCondition) InMonth(year int, month int) *RecordCondition {
+	atFrom := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	atTo := time.Date(year, time.Month(month+1), 1, 0, 0, 0, 0, time.Local)
+
+	c.AtFrom = &atFrom
+	c.AtTo = &atTo
+
+	return c
+}
+
+type RecordConnection struct {
+	Nodes       []*Record
+	PageInfo    *PageInfo
+	TotalAssets *int             // 先頭のRecordより前の資産合計。日時順以外で並べた場合はnil
+	Condition   *RecordCondition // totalCount等の集計に使う絞り込み条件
+}
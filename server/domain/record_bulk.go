@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// RecordBulkMaxCount は一括操作で一度に指定できるRecordの上限
+const RecordBulkMaxCount = 500
+
+// RecordBulkPatch は複数のRecordにまとめて適用する変更。nilやゼロ値の項目は変更しない
+type RecordBulkPatch struct {
+	AddTagNames    []string
+	RemoveTagNames []string
+	AssetID        *AssetID // 収入・支出のAssetを変更する。振替には適用できない
+	ShiftDays      int      // 日時を指定した日数だけずらす。負の値の場合は過去にずらす
+	Title          *string
+}
+
+func (p *RecordBulkPatch) IsEmpty() bool {
+	return len(p.AddTagNames) == 0 && len(p.RemoveTagNames) == 0 && p.AssetID == nil && p.ShiftDays == 0 && p.Title == nil
+}
+
+// ChangesAssetAmount は資産の推移（スナップショット）に影響する変更を含むかを返す
+func (p *RecordBulkPatch) ChangesAssetAmount() bool {
+	return p.AssetID != nil || p.ShiftDays != 0
+}
+
+// Apply はRecordとそのAssetChangeに変更を適用する。タグの変更は含まない
+func (p *RecordBulkPatch) Apply(record *Record, changes AssetChanges) error {
+	if p.AssetID != nil {
+		if record.RecordType == RecordTypeTransfer {
+			return xerrors.Errorf("asset of transfer record cannot be changed in bulk: %w", ErrInvalidRecordBulkPatch)
+		}
+		if len(changes) != 1 {
+			return xerrors.Errorf("expected 1 asset change for record %s, found %d: %w", record.ID, len(changes), ErrAssetChangeNotFound)
+		}
+		changes[0].AssetID = *p.AssetID
+	}
+
+	if p.ShiftDays != 0 {
+		record.At = record.At.AddDate(0, 0, p.ShiftDays)
+	}
+
+	if p.Title != nil {
+		record.Title = *p.Title
+	}
+
+	return nil
+}
+
+// RecordBulkResult は一括操作のRecordごとの結果。Errがnilでない場合はそのRecordの操作に失敗した
+type RecordBulkResult struct {
+	ID     RecordID
+	Record *Record // 更新後のRecord。削除や失敗した場合はnil
+	Err    error
+}
+
+// BulkRecordsPayload は一括操作の結果。すべてのRecordの操作に成功した場合のみ変更を反映する
+type BulkRecordsPayload struct {
+	Results   []*RecordBulkResult
+	Succeeded bool
+}
+
+// NewFailedBulkRecordsPayload は一括操作を取り消したときの結果を返す。変更は反映されないため、成功したRecordの結果も含めない
+func NewFailedBulkRecordsPayload(results []*RecordBulkResult) *BulkRecordsPayload {
+	for _, result := range results {
+		result.Record = nil
+	}
+
+	return &BulkRecordsPayload{
+		Results:   results,
+		Succeeded: false,
+	}
+}
+
+// EarliestAt は日時のうち最も古いものを返す。すべてゼロ値の場合はゼロ値を返す
+func EarliestAt(ats ...time.Time) time.Time {
+	var earliest time.Time
+	for _, at := range ats {
+		if at.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || at.Before(earliest) {
+			earliest = at
+		}
+	}
+
+	return earliest
+}
+
+func UniqueRecordIDs(ids []RecordID) []RecordID {
+	uniqueIDs := make([]RecordID, 0, len(ids))
+	seen := make(map[RecordID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+	return uniqueIDs
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	RecordCategoryIDSuffix = "RecordCategory"
+)
+
+type RecordCategoryID string
+
+func NewRecordCategoryID() RecordCategoryID {
+	return RecordCategoryID(NewUUIDv4(RecordCategoryIDSuffix))
+}
+
+// RecordCategory はRecordの分類（食費 > 外食 など）。収入・支出ごとに別の木構造を持つ
+// Tagと異なり、1つのRecordには1つのRecordCategoryのみ設定できる
+// 既存のRecordや分類を決めずに登録するRecordがあるため、分類は必須にせず、未設定のRecordは未分類として集計する
+type RecordCategory struct {
+	ID             RecordCategoryID
+	UserID         UserID
+	RecordType     RecordType
+	Name           string
+	ParentID       *RecordCategoryID // nilの場合は最上位の分類
+	ExpenseAccount *ExpenseAccount   // 青色申告決算書の科目。nilの場合は親の分類の科目を使う
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewRecordCategory(userID UserID, recordType RecordType, name string, parentID *RecordCategoryID) (*RecordCategory, error) {
+	if recordType != RecordTypeIncome && recordType != RecordTypeExpense {
+		return nil, xerrors.Errorf("record category must be income or expense, got %s: %w", recordType, ErrInvalidRecordCategory)
+	}
+
+	return &RecordCategory{
+		ID:         NewRecordCategoryID(),
+		UserID:     userID,
+		RecordType: recordType,
+		Name:       name,
+		ParentID:   parentID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// ValidateParent は親の分類として設定できるかを検証する
+// 収入・支出の種別が異なる場合や、自身または子孫を親にして循環する場合はエラーを返す
+func (c *RecordCategory) ValidateParent(parent *RecordCategory, categories RecordCategories) error {
+	if parent == nil {
+		return nil
+	}
+
+	if parent.RecordType != c.RecordType {
+		return xerrors.Errorf("parent record category must be %s: %w", c.RecordType, ErrInvalidRecordCategory)
+	}
+
+	for _, id := range categories.DescendantIDs(c.ID) {
+		if id == parent.ID {
+			return xerrors.Errorf("record category cannot be moved under itself or its descendants: %w", ErrInvalidRecordCategory)
+		}
+	}
+
+	return nil
+}
+
+// ValidateRecordType はRecordの種別に設定できる分類かを検証する
+func (c *RecordCategory) ValidateRecordType(recordType RecordType) error {
+	if c.RecordType != recordType {
+		return xerrors.Errorf("record category for %s cannot be set to %s record: %w", c.RecordType, recordType, ErrInvalidRecordCategory)
+	}
+
+	return nil
+}
+
+type RecordCategories []*RecordCategory
+
+// DescendantIDs は指定した分類とその子孫の分類のIDを返す
+func (categories RecordCategories) DescendantIDs(id RecordCategoryID) []RecordCategoryID {
+	childIDs := make(map[RecordCategoryID][]RecordCategoryID)
+	for parentID, children := range categories.childrenMap() {
+		for _, child := range children {
+			childIDs[parentID] = append(childIDs[parentID], child.ID)
+		}
+	}
+
+	return collectDescendantIDs(id, childIDs)
+}
+
+func (categories RecordCategories) childrenMap() map[RecordCategoryID]RecordCategories {
+	children := make(map[RecordCategoryID]RecordCategories)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	return children
+}
+
+// RecordCategoryAmount は分類ごとに集計したRecordの金額。CategoryIDがnilの場合は未分類のRecordの集計
+type RecordCategoryAmount struct {
+	CategoryID  *RecordCategoryID
+	Amount      int
+	RecordCount int
+}
+
+// RecordCategorySummary は分類ごとの集計結果。TotalAmountは子孫の分類の金額を含む
+type RecordCategorySummary struct {
+	Category         *RecordCategory // nilの場合は未分類
+	Depth            int
+	OwnAmount        int
+	TotalAmount      int
+	OwnRecordCount   int
+	TotalRecordCount int
+}
+
+// Summaries は分類ごとの金額を木構造に沿って積み上げた集計結果を、親→子の順（深さ優先）で返す
+// 未分類のRecordがある場合は末尾に追加する
+func (categories RecordCategories) Summaries(amounts []*RecordCategoryAmount) []*RecordCategorySummary {
+	amountMap := make(map[RecordCategoryID]*RecordCategoryAmount, len(amounts))
+	var uncategorized *RecordCategoryAmount
+	for _, amount := range amounts {
+		if amount.CategoryID == nil {
+			uncategorized = amount
+			continue
+		}
+		amountMap[*amount.CategoryID] = amount
+	}
+
+	children := categories.childrenMap()
+	summaries := make([]*RecordCategorySummary, 0, len(categories)+1)
+
+	var walk func(category *RecordCategory, depth int) *RecordCategorySummary
+	walk = func(category *RecordCategory, depth int) *RecordCategorySummary {
+		summary := &RecordCategorySummary{
+			Category: category,
+			Depth:    depth,
+		}
+		if amount, ok := amountMap[category.ID]; ok {
+			summary.OwnAmount = amount.Amount
+			summary.OwnRecordCount = amount.RecordCount
+		}
+		summary.TotalAmount = summary.OwnAmount
+		summary.TotalRecordCount = summary.OwnRecordCount
+
+		summaries = append(summaries, summary)
+
+		for _, child := range children[category.ID] {
+			childSummary := walk(child, depth+1)
+			summary.TotalAmount += childSummary.TotalAmount
+			summary.TotalRecordCount += childSummary.TotalRecordCount
+		}
+
+		return summary
+	}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			walk(category, 0)
+		}
+	}
+
+	if uncategorized != nil {
+		summaries = append(summaries, &RecordCategorySummary{
+			Depth:            0,
+			OwnAmount:        uncategorized.Amount,
+			TotalAmount:      uncategorized.Amount,
+			OwnRecordCount:   uncategorized.RecordCount,
+			TotalRecordCount: uncategorized.RecordCount,
+		})
+	}
+
+	return summaries
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"kakeibo-web-server/lib/kana"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DuplicateCandidateMaxAtDiff はAtがこれより離れたRecordは重複の候補にしない
+	DuplicateCandidateMaxAtDiff = 48 * time.Hour
+	// DuplicateCandidateMaxRange はduplicateCandidatesで一度に探せる期間の上限
+	DuplicateCandidateMaxRange = 366 * 24 * time.Hour
+
+	duplicateCandidateMinConfidence = 0.5
+	duplicateCandidateAtWeight      = 0.4
+	duplicateCandidateTitleWeight   = 0.6
+)
+
+// DuplicateCandidate は重複して登録された可能性があるRecordの組
+type DuplicateCandidate struct {
+	Records    []*Record // Atの古い順
+	Confidence float64   // 0〜1。1に近いほど重複の可能性が高い
+}
+
+// FindDuplicateCandidates はAssetと金額が一致し、Atが近くタイトルが似ているRecordの組を重複の候補として返す
+// 候補は確からしさの高い順に並べる
+func FindDuplicateCandidates(records Records, changes AssetChanges) []*DuplicateCandidate {
+	changeMap := make(map[RecordID]AssetChanges, len(records))
+	for _, change := range changes {
+		changeMap[change.RecordID] = append(changeMap[change.RecordID], change)
+	}
+
+	// 種別・Asset・金額がすべて一致するRecordだけを比較する
+	groups := make(map[string]Records)
+	for _, record := range records {
+		recordChanges, ok := changeMap[record.ID]
+		if !ok {
+			continue
+		}
+		key := duplicateGroupKey(record.RecordType, recordChanges)
+		groups[key] = append(groups[key], record)
+	}
+
+	candidates := make([]*DuplicateCandidate, 0)
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].At.Before(group[j].At)
+		})
+
+		for i, record := range group {
+			for _, other := range group[i+1:] {
+				atDiff := other.At.Sub(record.At)
+				if atDiff > DuplicateCandidateMaxAtDiff {
+					break
+				}
+
+				atScore := 1 - float64(atDiff)/float64(DuplicateCandidateMaxAtDiff)
+				confidence := duplicateCandidateAtWeight*atScore + duplicateCandidateTitleWeight*TitleSimilarity(record.Title, other.Title)
+				if confidence < duplicateCandidateMinConfidence {
+					continue
+				}
+
+				candidates = append(candidates, &DuplicateCandidate{
+					Records:    []*Record{record, other},
+					Confidence: confidence,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Records[0].At.Before(candidates[j].Records[0].At)
+	})
+
+	return candidates
+}
+
+func duplicateGroupKey(recordType RecordType, changes AssetChanges) string {
+	parts := make([]string, 0, len(changes)+1)
+	for _, change := range changes {
+		parts = append(parts, string(change.AssetID)+":"+strconv.Itoa(change.Amount))
+	}
+	sort.Strings(parts)
+
+	return string(recordType) + "/" + strings.Join(parts, "/")
+}
+
+// TitleSimilarity はタイトルの類似度を0〜1で返す
+// 全角・半角、大文字・小文字、カタカナ・ひらがな、空白の違いを無視し、編集距離を長い方の文字数で割って求める
+func TitleSimilarity(a, b string) float64 {
+	ra := []rune(normalizeForMatch(a))
+	rb := []rune(normalizeForMatch(b))
+
+	maxLength := max(len(ra), len(rb))
+	if maxLength == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshteinDistance(ra, rb))/float64(maxLength)
+}
+
+func normalizeForMatch(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, kana.Normalize(s))
+}
+
+func levenshteinDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	RecordItemIDSuffix = "RecordItem"
+
+	// RecordItemMaxCount は1つのRecordに登録できる明細の上限
+	RecordItemMaxCount = 200
+
+	recordItemNameMaxLength = 255
+)
+
+type RecordItemID string
+
+func NewRecordItemID() RecordItemID {
+	return RecordItemID(NewUUIDv4(RecordItemIDSuffix))
+}
+
+// TaxRate は消費税率
+type TaxRate string
+
+const (
+	TaxRateStandard TaxRate = "STANDARD" // 標準税率（10%）
+	TaxRateReduced  TaxRate = "REDUCED"  // 軽減税率（8%）。飲食料品など
+)
+
+// Percent は税率をパーセントで返す
+func (r TaxRate) Percent() int {
+	switch r {
+	case TaxRateStandard:
+		return 10
+	case TaxRateReduced:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (r TaxRate) IsValid() bool {
+	return r == TaxRateStandard || r == TaxRateReduced
+}
+
+// RecordItem はRecordの明細（レシートの1行）
+// 明細があるRecordでは、Tagごとの集計に明細のTagと金額を使う
+type RecordItem struct {
+	ID              RecordItemID
+	UserID          UserID
+	RecordID        RecordID
+	Name            string
+	Quantity        int
+	UnitPrice       int // 値引きの行は負の値にする
+	TaxRate         TaxRate
+	InclusiveAmount int    // 税込金額。単価が税抜の場合は税率ごとの消費税額を金額の比で割り振る
+	SortOrder       int    // Record内での表示順
+	Tags            []*Tag // record_item_tagから読み込む
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// RecordItemParam は明細を登録するときの入力
+type RecordItemParam struct {
+	Name      string
+	Quantity  int
+	UnitPrice int
+	TaxRate   TaxRate
+	TagNames  []string
+}
+
+func NewRecordItem(userID UserID, recordID RecordID, param *RecordItemParam, sortOrder int) (*RecordItem, error) {
+	name := strings.TrimSpace(param.Name)
+	if name == "" || utf8.RuneCountInString(name) > recordItemNameMaxLength {
+		return nil, xerrors.Errorf("item name must be 1 to %d characters: %w", recordItemNameMaxLength, ErrInvalidRecordItems)
+	}
+	if param.Quantity <= 0 {
+		return nil, xerrors.Errorf("quantity of item %s must be positive: %w", name, ErrInvalidRecordItems)
+	}
+	if !param.TaxRate.IsValid() {
+		return nil, xerrors.Errorf("tax rate %s of item %s is not supported: %w", param.TaxRate, name, ErrInvalidRecordItems)
+	}
+
+	return &RecordItem{
+		ID:        NewRecordItemID(),
+		UserID:    userID,
+		RecordID:  recordID,
+		Name:      name,
+		Quantity:  param.Quantity,
+		UnitPrice: param.UnitPrice,
+		TaxRate:   param.TaxRate,
+		SortOrder: sortOrder,
+		Tags:      make([]*Tag, 0),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// Amount は数量×単価
+func (i *RecordItem) Amount() int {
+	return i.Quantity * i.UnitPrice
+}
+
+type RecordItems []*RecordItem
+
+// NewRecordItems はparamsの順に明細を作成する。空のparamsの場合は明細なしを表す空のリストを返す
+func NewRecordItems(userID UserID, recordID RecordID, params []*RecordItemParam) (RecordItems, error) {
+	if len(params) > RecordItemMaxCount {
+		return nil, xerrors.Errorf("up to %d items can be registered: %w", RecordItemMaxCount, ErrInvalidRecordItems)
+	}
+
+	items := make(RecordItems, 0, len(params))
+	for i, param := range params {
+		item, err := NewRecordItem(userID, recordID, param, i)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (items RecordItems) TotalAmount() int {
+	total := 0
+	for _, item := range items {
+		total += item.Amount()
+	}
+	return total
+}
+
+// Validate は明細の合計がRecordの金額（AssetChangeの金額の絶対値）と一致するかを検証する
+// 単価が税抜の場合は、税率ごとに端数処理した消費税額を加えた税込の合計と比較する
+// 振替は明細を持てない。明細がない場合は常に成功する
+func (items RecordItems) Validate(record *Record, changes AssetChanges) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var amount int
+	switch record.RecordType {
+	case RecordTypeIncome:
+		change := changes.Income()
+		if change == nil {
+			return xerrors.Errorf("income of record %s: %w", record.ID, ErrAssetChangeNotFound)
+		}
+		amount = change.Amount
+	case RecordTypeExpense:
+		change := changes.Expense()
+		if change == nil {
+			return xerrors.Errorf("expense of record %s: %w", record.ID, ErrAssetChangeNotFound)
+		}
+		amount = -change.Amount
+	default:
+		return xerrors.Errorf("%s record cannot have items: %w", record.RecordType, ErrInvalidRecordItems)
+	}
+
+	total := items.TotalAmount()
+	if record.TaxMode == TaxModeExclusive {
+		total = 0
+		for _, breakdown := range items.TaxBreakdowns(record.TaxMode, record.TaxRounding) {
+			total += breakdown.InclusiveAmount
+		}
+	}
+	if total != amount {
+		return xerrors.Errorf("total of items %d does not match record amount %d: %w", total, amount, ErrInvalidRecordItems)
+	}
+
+	return nil
+}
+
+// TagAmount はTagごとの金額の集計。TagIDがnilの場合はTagのない明細・Recordの集計
+type TagAmount struct {
+	TagID       *TagID
+	Amount      int
+	RecordCount int
+}
+
+// TagSummary はTagごとの金額の集計結果
+type TagSummary struct {
+	Tag         *Tag // nilの場合はTagのない明細・Recordの集計
+	Amount      int
+	RecordCount int
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// RecordPatch はRecordの部分更新。nilの項目は変更しない
+type RecordPatch struct {
+	Title       *string
+	Description *string
+	At          *time.Time
+	RecordType  *RecordType // 種別を変換する。AssetChangeは変換後の種別に合わせて作り直す
+	AssetID     *AssetID    // 収入・支出のAsset
+	FromAssetID *AssetID    // 振替の出金元
+	ToAssetID   *AssetID    // 振替の入金先
+	Amount      *int
+	TagNames    []string // nilの場合は変更せず、空の場合はすべてのTagを外す
+	CategoryID  *RecordCategoryID
+	// ClearCategory がtrueの場合は未分類にする。種別を変換した場合はCategoryIDを指定しなければ未分類になる
+	ClearCategory bool
+	PayeeID       *PayeeID
+	ClearPayee    bool // trueの場合は支払先なしにする。振替に変換した場合も支払先なしになる
+	TaxRate       *TaxRate
+	ClearTaxRate  bool // trueの場合は課税対象外にする。振替に変換した場合も課税対象外になる
+	TaxMode       *TaxMode
+	// TaxRounding は税率・単価の表し方を変更する場合にRecordに記録する端数処理（ユーザーの現在の設定）
+	TaxRounding   *TaxRounding
+	BusinessRatio *int
+	// ClearBusinessRatio がtrueの場合はTag・Assetの既定値を使う。支出以外に変換した場合も既定値に戻す
+	ClearBusinessRatio bool
+}
+
+// AssetChangePlan はRecordのAssetChangeをどう変更するか
+type AssetChangePlan struct {
+	Updates AssetChanges
+	Inserts AssetChanges
+	Deletes AssetChanges
+}
+
+type plannedAssetChange struct {
+	assetID AssetID
+	amount  int
+}
+
+// recordAssetState はRecordのAssetChangeから読み取った現在の金額とAsset
+type recordAssetState struct {
+	fromAssetID *AssetID
+	toAssetID   *AssetID
+	amount      int
+	changes     AssetChanges // 出金元、入金先の順
+}
+
+func newRecordAssetState(record *Record, changes AssetChanges) (*recordAssetState, error) {
+	switch record.RecordType {
+	case RecordTypeIncome:
+		if len(changes) != 1 {
+			return nil, xerrors.Errorf("expected 1 asset change for income record, found %d: %w", len(changes), ErrAssetChangeNotFound)
+		}
+		return &recordAssetState{toAssetID: &changes[0].AssetID, amount: changes[0].Amount, changes: changes}, nil
+	case RecordTypeExpense:
+		if len(changes) != 1 {
+			return nil, xerrors.Errorf("expected 1 asset change for expense record, found %d: %w", len(changes), ErrAssetChangeNotFound)
+		}
+		return &recordAssetState{fromAssetID: &changes[0].AssetID, amount: -changes[0].Amount, changes: changes}, nil
+	case RecordTypeTransfer:
+		if len(changes) != 2 {
+			return nil, xerrors.Errorf("expected 2 asset changes for transfer record, found %d: %w", len(changes), ErrAssetChangeNotFound)
+		}
+		from, to := changes[0], changes[1]
+		if from.Amount > 0 {
+			from, to = to, from
+		}
+		return &recordAssetState{fromAssetID: &from.AssetID, toAssetID: &to.AssetID, amount: to.Amount, changes: AssetChanges{from, to}}, nil
+	default:
+		return nil, xerrors.Errorf("unsupported record type %s: %w", record.RecordType, ErrInvalidRecordPatch)
+	}
+}
+
+// Apply はRecordに変更を適用し、AssetChangeの変更内容を返す。Tag・分類・支払先の設定は含まない
+// 種別を変換する場合、指定しなかったAssetは変換前のAssetを引き継ぐ（支出→振替なら支出のAssetを出金元にする）
+func (p *RecordPatch) Apply(record *Record, changes AssetChanges) (*AssetChangePlan, error) {
+	state, err := newRecordAssetState(record, changes)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	recordType := record.RecordType
+	if p.RecordType != nil {
+		recordType = *p.RecordType
+	}
+
+	amount := state.amount
+	if p.Amount != nil {
+		if *p.Amount < 0 {
+			return nil, ErrInvalidRecordAmount
+		}
+		amount = *p.Amount
+	}
+
+	var desired []plannedAssetChange
+	switch recordType {
+	case RecordTypeIncome, RecordTypeExpense:
+		if p.FromAssetID != nil || p.ToAssetID != nil {
+			return nil, xerrors.Errorf("fromAssetID and toAssetID are only for transfer record: %w", ErrInvalidRecordPatch)
+		}
+		if p.CategoryID != nil && p.ClearCategory {
+			return nil, xerrors.Errorf("categoryID and clearCategory cannot be specified together: %w", ErrInvalidRecordPatch)
+		}
+		if p.PayeeID != nil && p.ClearPayee {
+			return nil, xerrors.Errorf("payeeID and clearPayee cannot be specified together: %w", ErrInvalidRecordPatch)
+		}
+		if p.TaxRate != nil && p.ClearTaxRate {
+			return nil, xerrors.Errorf("taxRate and clearTaxRate cannot be specified together: %w", ErrInvalidRecordPatch)
+		}
+		if p.BusinessRatio != nil && p.ClearBusinessRatio {
+			return nil, xerrors.Errorf("businessRatio and clearBusinessRatio cannot be specified together: %w", ErrInvalidRecordPatch)
+		}
+
+		assetID := p.AssetID
+		if assetID == nil && recordType == RecordTypeIncome {
+			assetID = firstAssetID(state.toAssetID, state.fromAssetID)
+		}
+		if assetID == nil && recordType == RecordTypeExpense {
+			assetID = firstAssetID(state.fromAssetID, state.toAssetID)
+		}
+
+		signedAmount := amount
+		if recordType == RecordTypeExpense {
+			signedAmount = -amount
+		}
+		desired = append(desired, plannedAssetChange{assetID: *assetID, amount: signedAmount})
+	case RecordTypeTransfer:
+		if p.AssetID != nil {
+			return nil, xerrors.Errorf("assetID is not for transfer record, use fromAssetID and toAssetID: %w", ErrInvalidRecordPatch)
+		}
+		if p.CategoryID != nil {
+			return nil, xerrors.Errorf("transfer record cannot have category: %w", ErrInvalidRecordPatch)
+		}
+		if p.PayeeID != nil {
+			return nil, xerrors.Errorf("transfer record cannot have payee: %w", ErrInvalidRecordPatch)
+		}
+		if p.TaxRate != nil {
+			return nil, xerrors.Errorf("transfer record cannot have tax rate: %w", ErrInvalidRecordPatch)
+		}
+
+		fromAssetID := firstAssetID(p.FromAssetID, state.fromAssetID)
+		toAssetID := firstAssetID(p.ToAssetID, state.toAssetID)
+		if fromAssetID == nil || toAssetID == nil {
+			return nil, xerrors.Errorf("fromAssetID and toAssetID are required to convert to transfer: %w", ErrInvalidRecordPatch)
+		}
+
+		desired = append(desired,
+			plannedAssetChange{assetID: *fromAssetID, amount: -amount},
+			plannedAssetChange{assetID: *toAssetID, amount: amount},
+		)
+	default:
+		return nil, xerrors.Errorf("unsupported record type %s: %w", recordType, ErrInvalidRecordPatch)
+	}
+
+	// 既存のAssetChangeを使い回し、足りない分は追加、余った分は削除する
+	plan := &AssetChangePlan{}
+	for i, d := range desired {
+		if i < len(state.changes) {
+			change := state.changes[i]
+			change.AssetID = d.assetID
+			change.Amount = d.amount
+			plan.Updates = append(plan.Updates, change)
+			continue
+		}
+		plan.Inserts = append(plan.Inserts, NewAssetChange(record.UserID, record.ID, d.assetID, d.amount))
+	}
+	if len(state.changes) > len(desired) {
+		plan.Deletes = append(plan.Deletes, state.changes[len(desired):]...)
+	}
+
+	if p.Title != nil {
+		record.Title = *p.Title
+	}
+	if p.Description != nil {
+		record.Description = *p.Description
+	}
+	if p.At != nil {
+		record.At = *p.At
+	}
+	if recordType != record.RecordType || p.ClearCategory {
+		record.CategoryID = nil
+	}
+	if recordType == RecordTypeTransfer || p.ClearPayee {
+		record.PayeeID = nil
+	}
+	record.RecordType = recordType
+
+	taxRate := record.TaxRate
+	if recordType == RecordTypeTransfer || p.ClearTaxRate {
+		taxRate = nil
+	}
+	if p.TaxRate != nil {
+		taxRate = p.TaxRate
+	}
+	taxMode := record.TaxMode
+	if p.TaxMode != nil {
+		taxMode = *p.TaxMode
+	}
+	taxRounding := record.TaxRounding
+	if (p.TaxRate != nil || p.TaxMode != nil) && p.TaxRounding != nil {
+		taxRounding = *p.TaxRounding
+	}
+	err = record.SetTax(taxRate, taxMode, taxRounding)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	businessRatio := record.BusinessRatio
+	if recordType != RecordTypeExpense || p.ClearBusinessRatio {
+		businessRatio = nil
+	}
+	if p.BusinessRatio != nil {
+		businessRatio = p.BusinessRatio
+	}
+	err = record.SetBusinessRatio(businessRatio)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return plan, nil
+}
+
+func firstAssetID(ids ...*AssetID) *AssetID {
+	for _, id := range ids {
+		if id != nil {
+			return id
+		}
+	}
+	return nil
+}
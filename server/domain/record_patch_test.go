@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"errors"
+	"kakeibo-web-server/lib/typeutil"
+	"testing"
+	"time"
+)
+
+const (
+	testUserID  UserID  = "user"
+	testAssetA  AssetID = "assetA"
+	testAssetB  AssetID = "assetB"
+	testPayeeID PayeeID = "payee"
+)
+
+func newTestRecord(t *testing.T, recordType RecordType, amount int) (*Record, AssetChanges) {
+	t.Helper()
+
+	at := time.Date(2026, 4, 1, 12, 0, 0, 0, time.Local)
+	switch recordType {
+	case RecordTypeIncome:
+		record, change, err := NewRecordIncomeWithAssetChange(testUserID, "title", "", at, testAssetA, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return record, AssetChanges{change}
+	case RecordTypeExpense:
+		record, change, err := NewRecordExpenseWithAssetChange(testUserID, "title", "", at, testAssetA, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return record, AssetChanges{change}
+	default:
+		record, from, to, err := NewRecordTransferWithAssetChanges(testUserID, "title", "", at, testAssetA, testAssetB, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return record, AssetChanges{to, from}
+	}
+}
+
+type testAssetChange struct {
+	assetID AssetID
+	amount  int
+}
+
+func TestRecordPatchApply(t *testing.T) {
+	categoryID := RecordCategoryID("category")
+
+	tests := []struct {
+		name       string
+		recordType RecordType
+		setup      func(record *Record)
+		patch      RecordPatch
+		wantErr    error
+		wantUpdate []testAssetChange
+		wantInsert []testAssetChange
+		wantDelete int
+		check      func(t *testing.T, record *Record)
+	}{
+		{
+			name:       "amount only",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{Amount: typeutil.Ptr(300)},
+			wantUpdate: []testAssetChange{{testAssetA, -300}},
+		},
+		{
+			name:       "negative amount",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{Amount: typeutil.Ptr(-1)},
+			wantErr:    ErrInvalidRecordAmount,
+		},
+		{
+			name:       "from asset on expense",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{FromAssetID: typeutil.Ptr(testAssetB)},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "category and clearCategory",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{CategoryID: &categoryID, ClearCategory: true},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "payee and clearPayee",
+			recordType: RecordTypeIncome,
+			patch:      RecordPatch{PayeeID: typeutil.Ptr(testPayeeID), ClearPayee: true},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "taxRate and clearTaxRate",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{TaxRate: typeutil.Ptr(TaxRateStandard), ClearTaxRate: true},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "businessRatio and clearBusinessRatio",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{BusinessRatio: typeutil.Ptr(50), ClearBusinessRatio: true},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "asset on transfer",
+			recordType: RecordTypeTransfer,
+			patch:      RecordPatch{AssetID: typeutil.Ptr(testAssetB)},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "tax rate on transfer",
+			recordType: RecordTypeTransfer,
+			patch:      RecordPatch{TaxRate: typeutil.Ptr(TaxRateStandard)},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "convert expense to transfer keeps asset as source",
+			recordType: RecordTypeExpense,
+			setup: func(record *Record) {
+				record.CategoryID = &categoryID
+				record.PayeeID = typeutil.Ptr(testPayeeID)
+				record.TaxRate = typeutil.Ptr(TaxRateStandard)
+				record.BusinessRatio = typeutil.Ptr(50)
+			},
+			patch:      RecordPatch{RecordType: typeutil.Ptr(RecordTypeTransfer), ToAssetID: typeutil.Ptr(testAssetB)},
+			wantUpdate: []testAssetChange{{testAssetA, -100}},
+			wantInsert: []testAssetChange{{testAssetB, 100}},
+			check: func(t *testing.T, record *Record) {
+				if record.RecordType != RecordTypeTransfer {
+					t.Errorf("RecordType = %s, want %s", record.RecordType, RecordTypeTransfer)
+				}
+				if record.CategoryID != nil || record.PayeeID != nil || record.TaxRate != nil || record.BusinessRatio != nil {
+					t.Errorf("category, payee, tax rate and business ratio must be cleared: %+v", record)
+				}
+			},
+		},
+		{
+			name:       "convert expense to transfer without destination",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{RecordType: typeutil.Ptr(RecordTypeTransfer)},
+			wantErr:    ErrInvalidRecordPatch,
+		},
+		{
+			name:       "convert transfer to income uses destination",
+			recordType: RecordTypeTransfer,
+			patch:      RecordPatch{RecordType: typeutil.Ptr(RecordTypeIncome), Amount: typeutil.Ptr(200)},
+			wantUpdate: []testAssetChange{{testAssetB, 200}},
+			wantDelete: 1,
+		},
+		{
+			name:       "convert expense to income clears category and business ratio",
+			recordType: RecordTypeExpense,
+			setup: func(record *Record) {
+				record.CategoryID = &categoryID
+				record.BusinessRatio = typeutil.Ptr(50)
+			},
+			patch:      RecordPatch{RecordType: typeutil.Ptr(RecordTypeIncome)},
+			wantUpdate: []testAssetChange{{testAssetA, 100}},
+			check: func(t *testing.T, record *Record) {
+				if record.CategoryID != nil {
+					t.Errorf("CategoryID = %v, want nil", *record.CategoryID)
+				}
+				if record.BusinessRatio != nil {
+					t.Errorf("BusinessRatio = %d, want nil", *record.BusinessRatio)
+				}
+			},
+		},
+		{
+			name:       "tax rounding is stamped when tax rate changes",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{TaxRate: typeutil.Ptr(TaxRateReduced), TaxRounding: typeutil.Ptr(TaxRoundingCeil)},
+			wantUpdate: []testAssetChange{{testAssetA, -100}},
+			check: func(t *testing.T, record *Record) {
+				if record.TaxRate == nil || *record.TaxRate != TaxRateReduced {
+					t.Errorf("TaxRate = %v, want %s", record.TaxRate, TaxRateReduced)
+				}
+				if record.TaxRounding != TaxRoundingCeil {
+					t.Errorf("TaxRounding = %s, want %s", record.TaxRounding, TaxRoundingCeil)
+				}
+			},
+		},
+		{
+			name:       "tax rounding is kept when tax is not changed",
+			recordType: RecordTypeExpense,
+			patch:      RecordPatch{Title: typeutil.Ptr("new"), TaxRounding: typeutil.Ptr(TaxRoundingCeil)},
+			wantUpdate: []testAssetChange{{testAssetA, -100}},
+			check: func(t *testing.T, record *Record) {
+				if record.Title != "new" {
+					t.Errorf("Title = %s, want new", record.Title)
+				}
+				if record.TaxRounding != TaxRoundingFloor {
+					t.Errorf("TaxRounding = %s, want %s", record.TaxRounding, TaxRoundingFloor)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, changes := newTestRecord(t, tt.recordType, 100)
+			if tt.setup != nil {
+				tt.setup(record)
+			}
+
+			plan, err := tt.patch.Apply(record, changes)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			assertAssetChanges(t, "Updates", plan.Updates, tt.wantUpdate)
+			assertAssetChanges(t, "Inserts", plan.Inserts, tt.wantInsert)
+			if len(plan.Deletes) != tt.wantDelete {
+				t.Errorf("len(Deletes) = %d, want %d", len(plan.Deletes), tt.wantDelete)
+			}
+			if tt.check != nil {
+				tt.check(t, record)
+			}
+		})
+	}
+}
+
+func assertAssetChanges(t *testing.T, name string, got AssetChanges, want []testAssetChange) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("len(%s) = %d, want %d", name, len(got), len(want))
+	}
+	for i, change := range got {
+		if change.AssetID != want[i].assetID || change.Amount != want[i].amount {
+			t.Errorf("%s[%d] = {%s %d}, want {%s %d}", name, i, change.AssetID, change.Amount, want[i].assetID, want[i].amount)
+		}
+	}
+}
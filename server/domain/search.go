@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	SearchSortKeyScore         = "SCORE"
+	SearchHighlightContextSize = 20      // ハイライトの前後に残す文字数
+	searchNgramTokenSize       = 2       // MySQLのngram_token_sizeのデフォルト値
+	SearchScoreScale           = 1000000 // スコアを整数で扱うときの倍率（小数点以下6桁）
+)
+
+// RecordWithScore は全文検索の関連度スコアを持つRecord
+// カーソルで浮動小数点数を比較しないように、スコアはSearchScoreScale倍した整数で持つ
+type RecordWithScore struct {
+	Record
+	ScoreKey int64
+}
+
+func (r RecordWithScore) Score() float64 {
+	return float64(r.ScoreKey) / SearchScoreScale
+}
+
+func (r RecordWithScore) CursorValue() string {
+	return strconv.FormatInt(r.ScoreKey, 10)
+}
+
+// ParseSearchScoreCursor はカーソルの値からSearchScoreScale倍したスコアを取り出す
+func ParseSearchScoreCursor(value string) (int64, error) {
+	scoreKey, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid search cursor %q: %w", value, ErrInvalidPageParam)
+	}
+
+	return scoreKey, nil
+}
+
+type RecordSearchResult struct {
+	Record *Record
+	Score  float64
+	Query  string // ハイライトの生成に使う検索文字列
+}
+
+type RecordSearchConnection struct {
+	Nodes     []*RecordSearchResult
+	PageInfo  *PageInfo
+	Query     string
+	Condition *RecordCondition // totalCountの集計に使う絞り込み条件
+}
+
+type HighlightFragment struct {
+	Text    string
+	Matched bool
+}
+
+type SearchHighlight struct {
+	Field     SearchHighlightField
+	Fragments []*HighlightFragment
+}
+
+// SearchHighlightTarget はハイライトの対象となるフィールドとその文字列
+type SearchHighlightTarget struct {
+	Field SearchHighlightField
+	Text  string
+}
+
+// NewSearchHighlights は対象のうち検索語句に一致するものだけのハイライトを返す
+func NewSearchHighlights(targets []SearchHighlightTarget, query string) []*SearchHighlight {
+	highlights := make([]*SearchHighlight, 0, len(targets))
+	for _, target := range targets {
+		highlight := NewSearchHighlight(target.Field, target.Text, query)
+		if highlight != nil {
+			highlights = append(highlights, highlight)
+		}
+	}
+
+	return highlights
+}
+
+// SearchTerms は検索文字列を空白（全角を含む）で区切った語句を返す
+func SearchTerms(query string) []string {
+	return strings.Fields(strings.ReplaceAll(query, "　", " "))
+}
+
+// NewSearchHighlight はtextのうち検索語句に一致する部分を分割したハイライトを返す
+// 語句そのものが見つからない場合は、ngramパーサーと同じ単位（2文字）で一致する部分をハイライトする
+// 一致する部分がない場合はnilを返す
+func NewSearchHighlight(field SearchHighlightField, text string, query string) *SearchHighlight {
+	terms := SearchTerms(query)
+
+	ranges := findMatchRanges(text, terms)
+	if len(ranges) == 0 {
+		ranges = findMatchRanges(text, ngramTokens(terms))
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	return &SearchHighlight{
+		Field:     field,
+		Fragments: newHighlightFragments(text, mergeMatchRanges(ranges)),
+	}
+}
+
+type matchRange struct {
+	start int // バイト単位
+	end   int
+}
+
+func findMatchRanges(text string, terms []string) []matchRange {
+	lowerText := strings.ToLower(text)
+	ranges := make([]matchRange, 0)
+
+	for _, term := range terms {
+		lowerTerm := strings.ToLower(term)
+		if lowerTerm == "" || len(lowerTerm) != len(term) {
+			// 小文字化でバイト長が変わる文字を含む場合は位置がずれるため扱わない
+			continue
+		}
+
+		offset := 0
+		for {
+			index := strings.Index(lowerText[offset:], lowerTerm)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			ranges = append(ranges, matchRange{start: start, end: start + len(lowerTerm)})
+			offset = start + len(lowerTerm)
+		}
+	}
+
+	return ranges
+}
+
+func ngramTokens(terms []string) []string {
+	tokens := make([]string, 0)
+	for _, term := range terms {
+		runes := []rune(term)
+		if len(runes) <= searchNgramTokenSize {
+			continue
+		}
+		for i := 0; i+searchNgramTokenSize <= len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+searchNgramTokenSize]))
+		}
+	}
+
+	return tokens
+}
+
+func mergeMatchRanges(ranges []matchRange) []matchRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	merged := make([]matchRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(merged) > 0 && r.start <= merged[len(merged)-1].end {
+			if r.end > merged[len(merged)-1].end {
+				merged[len(merged)-1].end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// newHighlightFragments は一致した部分とその前後SearchHighlightContextSize文字を残し、それ以外を省略したフラグメントを返す
+func newHighlightFragments(text string, ranges []matchRange) []*HighlightFragment {
+	fragments := make([]*HighlightFragment, 0, len(ranges)*2+1)
+
+	cursor := 0
+	for i, r := range ranges {
+		before := text[cursor:r.start]
+		if i == 0 {
+			before = trimHead(before, SearchHighlightContextSize)
+		} else {
+			before = trimMiddle(before, SearchHighlightContextSize)
+		}
+		if before != "" {
+			fragments = append(fragments, &HighlightFragment{Text: before, Matched: false})
+		}
+
+		fragments = append(fragments, &HighlightFragment{Text: text[r.start:r.end], Matched: true})
+		cursor = r.end
+	}
+
+	after := trimTail(text[cursor:], SearchHighlightContextSize)
+	if after != "" {
+		fragments = append(fragments, &HighlightFragment{Text: after, Matched: false})
+	}
+
+	return fragments
+}
+
+func trimHead(s string, size int) string {
+	if utf8.RuneCountInString(s) <= size {
+		return s
+	}
+	runes := []rune(s)
+	return "…" + string(runes[len(runes)-size:])
+}
+
+func trimTail(s string, size int) string {
+	if utf8.RuneCountInString(s) <= size {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:size]) + "…"
+}
+
+func trimMiddle(s string, size int) string {
+	if utf8.RuneCountInString(s) <= size*2 {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:size]) + "…" + string(runes[len(runes)-size:])
+}
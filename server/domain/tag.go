@@ -1,6 +1,15 @@
 package domain
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/xerrors"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
 
 const (
 	TagIDSuffix = "Tag"
@@ -13,21 +22,95 @@ func NewTagID() TagID {
 }
 
 type Tag struct {
-	ID        TagID
-	UserID    UserID
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            TagID
+	UserID        UserID
+	Name          string
+	Color         *string // #RRGGBB形式
+	Icon          *string // 絵文字またはアイコンのキー
+	Archived      bool    // アーカイブしたTagは候補に表示しないが、過去のRecordには付与されたまま残す
+	SortOrder     int     // ユーザーが指定した並び順（昇順）
+	BusinessRatio *int    // このTagを付与した支出の事業割合の既定値（%）。nilの場合は既定値なし
+	Version       int     // 更新のたびに増える。楽観的排他制御に使う
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
-func NewTag(userID UserID, name string) *Tag {
-	return &Tag{
+// NormalizeTagName はTag名の表記ゆれを吸収するため、全角・半角をNFKCで統一し前後の空白を取り除く
+func NormalizeTagName(name string) string {
+	return strings.TrimSpace(norm.NFKC.String(name))
+}
+
+// NormalizeTagNames はTag名を正規化し、正規化後に重複する名前や空の名前を取り除く
+func NormalizeTagNames(names []string) []string {
+	normalizedNames := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		normalizedName := NormalizeTagName(name)
+		if normalizedName == "" {
+			continue
+		}
+		if _, ok := seen[normalizedName]; ok {
+			continue
+		}
+		seen[normalizedName] = struct{}{}
+		normalizedNames = append(normalizedNames, normalizedName)
+	}
+
+	return normalizedNames
+}
+
+func NewTag(userID UserID, name string) (*Tag, error) {
+	tag := &Tag{
 		ID:        NewTagID(),
 		UserID:    userID,
-		Name:      name,
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	err := tag.Rename(name)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tag, nil
+}
+
+// Rename は正規化した名前に変更する。正規化すると空になる名前は指定できない
+func (t *Tag) Rename(name string) error {
+	normalizedName := NormalizeTagName(name)
+	if normalizedName == "" {
+		return xerrors.Errorf("tag name is required: %w", ErrInvalidTag)
+	}
+	t.Name = normalizedName
+
+	return nil
+}
+
+// SetColor は色を設定する。空文字の場合は色を削除する
+func (t *Tag) SetColor(color string) error {
+	if color == "" {
+		t.Color = nil
+		return nil
+	}
+
+	if !tagColorPattern.MatchString(color) {
+		return xerrors.Errorf("tag color must be #RRGGBB format, got %s: %w", color, ErrInvalidTagColor)
+	}
+
+	color = strings.ToUpper(color)
+	t.Color = &color
+
+	return nil
+}
+
+// SetIcon はアイコンを設定する。空文字の場合はアイコンを削除する
+func (t *Tag) SetIcon(icon string) {
+	if icon == "" {
+		t.Icon = nil
+		return
+	}
+
+	t.Icon = &icon
 }
 
 type Tags []*Tag
@@ -41,18 +124,78 @@ func (t Tags) ContainsByName(name string) bool {
 	return false
 }
 
-func NewTagsNotExist(userID UserID, existTags Tags, names []string) []*Tag {
+func NewTagsNotExist(userID UserID, existTags Tags, names []string) ([]*Tag, error) {
 	tags := make([]*Tag, 0, len(names))
-	for _, name := range names {
-		if !existTags.ContainsByName(name) {
-			tags = append(tags, NewTag(userID, name))
+	for _, name := range NormalizeTagNames(names) {
+		if existTags.ContainsByName(name) {
+			continue
+		}
+		tag, err := NewTag(userID, name)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
 		}
+		tags = append(tags, tag)
 	}
 
-	return tags
+	return tags, nil
+}
+
+func (t Tags) IDs() []TagID {
+	ids := make([]TagID, 0, len(t))
+	for _, tag := range t {
+		ids = append(ids, tag.ID)
+	}
+	return ids
+}
+
+func UniqueTagIDs(ids []TagID) []TagID {
+	uniqueIDs := make([]TagID, 0, len(ids))
+	seen := make(map[TagID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+	return uniqueIDs
+}
+
+// TagCondition はRecordに付与されたTagの名前による絞り込み条件
+// ANYはいずれかのTag、ALLはすべてのTagが付与されたRecord、NONEはいずれのTagも付与されていないRecordに一致する
+type TagCondition struct {
+	Names []string
+	Mode  TagMatchMode
+}
+
+func (c TagCondition) UniqueNames() []string {
+	names := make([]string, 0, len(c.Names))
+	seen := make(map[string]struct{}, len(c.Names))
+	for _, name := range c.Names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// TagStat はTagの利用状況
+type TagStat struct {
+	TagID       TagID
+	RecordCount int
+	LastUsedAt  *time.Time // Tagが付与されたRecordのうち最も新しい日時。未使用の場合はnil
 }
 
 type TagWithRecordID struct {
 	Tag
 	RecordID RecordID
 }
+
+type TagConnection struct {
+	Nodes           []*Tag
+	PageInfo        *PageInfo
+	IncludeArchived bool
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"kakeibo-web-server/lib/kana"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	TagSuggestionMaxLimit = 50
+
+	// 各指標の重み。入力中の文字列に一致することを最も重視する
+	tagSuggestionPrefixWeight  = 3.0
+	tagSuggestionTitleWeight   = 2.0
+	tagSuggestionAssetWeight   = 1.0
+	tagSuggestionRecencyWeight = 1.0
+	tagSuggestionUsageWeight   = 0.5
+
+	tagSuggestionRecencyHalfLifeDays = 30.0
+)
+
+// TagUsageCount は条件に一致するRecordでTagが使われた回数
+type TagUsageCount struct {
+	TagID TagID
+	Count int
+}
+
+// TagSuggestionSignals はTagの候補の順位付けに使う利用履歴
+type TagSuggestionSignals struct {
+	Stats       []*TagStat       // Tagごとの利用回数と最終利用日時
+	TitleCounts []*TagUsageCount // 似たタイトルのRecordで使われた回数
+	AssetCounts []*TagUsageCount // 同じAssetのRecordで使われた回数
+}
+
+// RankTagSuggestions は入力中の文字列と利用履歴からTagの候補を順位付けし、上位limit件を返す
+// アーカイブしたTagは候補に含めない。prefixを指定した場合は、ひらがな・カタカナ・ローマ字の違いを無視して前方一致または部分一致するTagのみを候補にする
+func RankTagSuggestions(tags Tags, prefix string, signals *TagSuggestionSignals, now time.Time, limit int) Tags {
+	matcher := newTagPrefixMatcher(prefix)
+
+	stats := make(map[TagID]*TagStat, len(signals.Stats))
+	maxUsage := 0
+	for _, stat := range signals.Stats {
+		stats[stat.TagID] = stat
+		maxUsage = max(maxUsage, stat.RecordCount)
+	}
+	titleCounts, maxTitleCount := usageCountMap(signals.TitleCounts)
+	assetCounts, maxAssetCount := usageCountMap(signals.AssetCounts)
+
+	type scoredTag struct {
+		tag   *Tag
+		score float64
+	}
+	scoredTags := make([]scoredTag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Archived {
+			continue
+		}
+
+		prefixScore := matcher.score(tag.Name)
+		if matcher.prefix != "" && prefixScore == 0 {
+			continue
+		}
+
+		score := tagSuggestionPrefixWeight * prefixScore
+		score += tagSuggestionTitleWeight * usageRatio(titleCounts[tag.ID], maxTitleCount)
+		score += tagSuggestionAssetWeight * usageRatio(assetCounts[tag.ID], maxAssetCount)
+		if stat, ok := stats[tag.ID]; ok {
+			score += tagSuggestionUsageWeight * usageRatio(stat.RecordCount, maxUsage)
+			if stat.LastUsedAt != nil {
+				days := max(now.Sub(*stat.LastUsedAt).Hours()/24, 0)
+				score += tagSuggestionRecencyWeight * tagSuggestionRecencyHalfLifeDays / (tagSuggestionRecencyHalfLifeDays + days)
+			}
+		}
+
+		scoredTags = append(scoredTags, scoredTag{tag: tag, score: score})
+	}
+
+	sort.SliceStable(scoredTags, func(i, j int) bool {
+		if scoredTags[i].score != scoredTags[j].score {
+			return scoredTags[i].score > scoredTags[j].score
+		}
+		return scoredTags[i].tag.Name < scoredTags[j].tag.Name
+	})
+
+	ranked := make(Tags, 0, min(limit, len(scoredTags)))
+	for i := 0; i < len(scoredTags) && i < limit; i++ {
+		ranked = append(ranked, scoredTags[i].tag)
+	}
+
+	return ranked
+}
+
+// tagPrefixMatcher は入力中の文字列とTag名を、表記の違いを吸収して比較する
+type tagPrefixMatcher struct {
+	prefix string
+	keys   []string // 比較用に正規化した入力文字列（かな表記とローマ字をかなに変換したもの）
+}
+
+func newTagPrefixMatcher(prefix string) *tagPrefixMatcher {
+	normalized := kana.Normalize(prefix)
+	keys := []string{normalized}
+
+	romaji := kana.TrimIncompleteRomaji(kana.RomajiToHiragana(normalized))
+	if romaji != "" && romaji != normalized {
+		keys = append(keys, romaji)
+	}
+
+	return &tagPrefixMatcher{
+		prefix: NormalizeTagName(prefix),
+		keys:   keys,
+	}
+}
+
+// score は前方一致の場合に1、表記の違いを無視した前方一致の場合に0.8、部分一致の場合に0.3を返す
+func (m *tagPrefixMatcher) score(name string) float64 {
+	if m.prefix == "" {
+		return 0
+	}
+	if strings.HasPrefix(name, m.prefix) {
+		return 1
+	}
+
+	normalizedName := kana.Normalize(name)
+	for _, key := range m.keys {
+		if strings.HasPrefix(normalizedName, key) {
+			return 0.8
+		}
+	}
+	for _, key := range m.keys {
+		if strings.Contains(normalizedName, key) {
+			return 0.3
+		}
+	}
+
+	return 0
+}
+
+func usageCountMap(counts []*TagUsageCount) (map[TagID]int, int) {
+	countMap := make(map[TagID]int, len(counts))
+	maxCount := 0
+	for _, count := range counts {
+		countMap[count.TagID] = count.Count
+		maxCount = max(maxCount, count.Count)
+	}
+
+	return countMap, maxCount
+}
+
+func usageRatio(value, maxValue int) float64 {
+	if maxValue == 0 {
+		return 0
+	}
+
+	return float64(value) / float64(maxValue)
+}
@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// TaxSummaryMaxRange はtaxSummaryで一度に集計できる期間の上限
+const TaxSummaryMaxRange = 366 * 24 * time.Hour
+
+// taxRates は集計結果に並べる税率の順
+var taxRates = []TaxRate{TaxRateStandard, TaxRateReduced}
+
+// TaxMode は明細の単価が税込か税抜か
+// 明細のないRecordの金額は実際に支払った額のため、常に税込として扱う
+type TaxMode string
+
+const (
+	TaxModeInclusive TaxMode = "INCLUSIVE"
+	TaxModeExclusive TaxMode = "EXCLUSIVE"
+)
+
+func (m TaxMode) IsValid() bool {
+	return m == TaxModeInclusive || m == TaxModeExclusive
+}
+
+// TaxRounding は消費税額の1円未満の端数の処理。ユーザーごとに設定し、税率・明細を設定した時点の設定をRecordに記録する
+type TaxRounding string
+
+const (
+	TaxRoundingFloor TaxRounding = "FLOOR" // 切り捨て
+	TaxRoundingRound TaxRounding = "ROUND" // 四捨五入
+	TaxRoundingCeil  TaxRounding = "CEIL"  // 切り上げ
+)
+
+func (r TaxRounding) IsValid() bool {
+	return r == TaxRoundingFloor || r == TaxRoundingRound || r == TaxRoundingCeil
+}
+
+// divide はnumerator/denominatorを端数処理して返す。denominatorは正の値
+func (r TaxRounding) divide(numerator, denominator int) int {
+	quotient := numerator / denominator
+	remainder := numerator % denominator
+	if remainder == 0 {
+		return quotient
+	}
+
+	switch r {
+	case TaxRoundingCeil:
+		if remainder > 0 {
+			quotient++
+		}
+	case TaxRoundingRound:
+		if 2*abs(remainder) >= denominator {
+			if numerator > 0 {
+				quotient++
+			} else {
+				quotient--
+			}
+		}
+	default:
+		if remainder < 0 {
+			quotient--
+		}
+	}
+
+	return quotient
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// TaxBreakdown は税率ごとの税抜金額と消費税額
+type TaxBreakdown struct {
+	TaxRate         TaxRate
+	TaxableAmount   int // 税抜金額
+	TaxAmount       int
+	InclusiveAmount int // 税込金額
+}
+
+// NewTaxBreakdown はmodeで表したamountから税抜金額と消費税額を求める
+// インボイス制度にあわせ、端数処理は税率ごとの合計に対して1回だけ行う
+func NewTaxBreakdown(rate TaxRate, amount int, mode TaxMode, rounding TaxRounding) *TaxBreakdown {
+	percent := rate.Percent()
+	if mode == TaxModeExclusive {
+		tax := rounding.divide(amount*percent, 100)
+		return &TaxBreakdown{
+			TaxRate:         rate,
+			TaxableAmount:   amount,
+			TaxAmount:       tax,
+			InclusiveAmount: amount + tax,
+		}
+	}
+
+	tax := rounding.divide(amount*percent, 100+percent)
+	return &TaxBreakdown{
+		TaxRate:         rate,
+		TaxableAmount:   amount - tax,
+		TaxAmount:       tax,
+		InclusiveAmount: amount,
+	}
+}
+
+// TaxBreakdowns は明細を税率ごとに合計し、modeで表した単価から消費税額を求める
+func (items RecordItems) TaxBreakdowns(mode TaxMode, rounding TaxRounding) []*TaxBreakdown {
+	amounts := make(map[TaxRate]int, len(taxRates))
+	for _, item := range items {
+		amounts[item.TaxRate] += item.Amount()
+	}
+
+	breakdowns := make([]*TaxBreakdown, 0, len(amounts))
+	for _, rate := range taxRates {
+		amount, ok := amounts[rate]
+		if !ok {
+			continue
+		}
+		breakdowns = append(breakdowns, NewTaxBreakdown(rate, amount, mode, rounding))
+	}
+
+	return breakdowns
+}
+
+// SetInclusiveAmounts は明細ごとの税込金額を設定する。税率ごとの税込金額の合計はTaxBreakdownsと一致する
+// 割り切れない消費税額は税率ごとの最後の明細に寄せる
+func (items RecordItems) SetInclusiveAmounts(mode TaxMode, rounding TaxRounding) {
+	groups := make(map[TaxRate]RecordItems, len(taxRates))
+	for _, item := range items {
+		groups[item.TaxRate] = append(groups[item.TaxRate], item)
+	}
+
+	for _, breakdown := range items.TaxBreakdowns(mode, rounding) {
+		group := groups[breakdown.TaxRate]
+		if mode != TaxModeExclusive {
+			for _, item := range group {
+				item.InclusiveAmount = item.Amount()
+			}
+			continue
+		}
+
+		allocated := 0
+		for i, item := range group {
+			tax := breakdown.TaxAmount - allocated
+			if i < len(group)-1 {
+				tax = 0
+				if breakdown.TaxableAmount != 0 {
+					tax = breakdown.TaxAmount * item.Amount() / breakdown.TaxableAmount
+				}
+			}
+			allocated += tax
+			item.InclusiveAmount = item.Amount() + tax
+		}
+	}
+}
+
+// TaxBreakdowns はRecordの税率ごとの内訳を返す。明細がある場合は明細から、ない場合はRecordの税率と金額から求める
+// 端数処理はRecordに記録したものを使う。税率が設定されていないRecordは課税対象外として空のリストを返す
+func (r *Record) TaxBreakdowns(items RecordItems, amount int) []*TaxBreakdown {
+	if len(items) > 0 {
+		return items.TaxBreakdowns(r.TaxMode, r.TaxRounding)
+	}
+	if r.TaxRate == nil {
+		return make([]*TaxBreakdown, 0)
+	}
+
+	return []*TaxBreakdown{NewTaxBreakdown(*r.TaxRate, amount, TaxModeInclusive, r.TaxRounding)}
+}
+
+// SetTax はRecordの税率と明細の単価の表し方、その時点の端数処理を設定する。振替には税率を設定できない
+func (r *Record) SetTax(rate *TaxRate, mode TaxMode, rounding TaxRounding) error {
+	if rate != nil {
+		if r.RecordType == RecordTypeTransfer {
+			return xerrors.Errorf("transfer record cannot have tax rate: %w", ErrInvalidTax)
+		}
+		if !rate.IsValid() {
+			return xerrors.Errorf("tax rate %s is not supported: %w", *rate, ErrInvalidTax)
+		}
+	}
+	if !mode.IsValid() {
+		return xerrors.Errorf("tax mode %s is not supported: %w", mode, ErrInvalidTax)
+	}
+	if !rounding.IsValid() {
+		return xerrors.Errorf("tax rounding %s is not supported: %w", rounding, ErrInvalidTax)
+	}
+
+	r.TaxRate = rate
+	r.TaxMode = mode
+	r.TaxRounding = rounding
+
+	return nil
+}
+
+// TaxRateSummary は税率ごとの集計
+type TaxRateSummary struct {
+	TaxBreakdown
+	RecordCount int
+}
+
+// TaxSummary は期間内の収入・支出の消費税の集計
+type TaxSummary struct {
+	Rounding TaxRounding // ユーザーの現在の設定。集計にはRecordごとに記録した端数処理を使う
+	Income   []*TaxRateSummary
+	Expense  []*TaxRateSummary
+}
+
+// NewTaxSummary はRecordごとに求めた税率ごとの内訳を、収入・支出に分けて合計する。roundingは結果に表示するユーザーの現在の設定
+func NewTaxSummary(records Records, changes AssetChanges, items RecordItems, rounding TaxRounding) *TaxSummary {
+	changeMap := make(map[RecordID]AssetChanges, len(records))
+	for _, change := range changes {
+		changeMap[change.RecordID] = append(changeMap[change.RecordID], change)
+	}
+	itemMap := make(map[RecordID]RecordItems, len(records))
+	for _, item := range items {
+		itemMap[item.RecordID] = append(itemMap[item.RecordID], item)
+	}
+
+	totals := map[RecordType]map[TaxRate]*TaxRateSummary{
+		RecordTypeIncome:  make(map[TaxRate]*TaxRateSummary),
+		RecordTypeExpense: make(map[TaxRate]*TaxRateSummary),
+	}
+	for _, record := range records {
+		total, ok := totals[record.RecordType]
+		if !ok {
+			continue
+		}
+
+		var amount int
+		if record.RecordType == RecordTypeIncome {
+			if change := changeMap[record.ID].Income(); change != nil {
+				amount = change.Amount
+			}
+		} else {
+			if change := changeMap[record.ID].Expense(); change != nil {
+				amount = -change.Amount
+			}
+		}
+
+		for _, breakdown := range record.TaxBreakdowns(itemMap[record.ID], amount) {
+			summary, ok := total[breakdown.TaxRate]
+			if !ok {
+				summary = &TaxRateSummary{TaxBreakdown: TaxBreakdown{TaxRate: breakdown.TaxRate}}
+				total[breakdown.TaxRate] = summary
+			}
+			summary.TaxableAmount += breakdown.TaxableAmount
+			summary.TaxAmount += breakdown.TaxAmount
+			summary.InclusiveAmount += breakdown.InclusiveAmount
+			summary.RecordCount++
+		}
+	}
+
+	return &TaxSummary{
+		Rounding: rounding,
+		Income:   sortTaxRateSummaries(totals[RecordTypeIncome]),
+		Expense:  sortTaxRateSummaries(totals[RecordTypeExpense]),
+	}
+}
+
+func sortTaxRateSummaries(summaryMap map[TaxRate]*TaxRateSummary) []*TaxRateSummary {
+	summaries := make([]*TaxRateSummary, 0, len(summaryMap))
+	for _, rate := range taxRates {
+		if summary, ok := summaryMap[rate]; ok {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,126 @@
+package domain
+
+import "testing"
+
+func TestTaxRoundingDivide(t *testing.T) {
+	tests := []struct {
+		name        string
+		rounding    TaxRounding
+		numerator   int
+		denominator int
+		want        int
+	}{
+		{"floor exact", TaxRoundingFloor, 1000, 100, 10},
+		{"floor positive", TaxRoundingFloor, 1099, 100, 10},
+		{"floor negative", TaxRoundingFloor, -1001, 100, -11},
+		{"ceil positive", TaxRoundingCeil, 1001, 100, 11},
+		{"ceil negative", TaxRoundingCeil, -1099, 100, -10},
+		{"round below half", TaxRoundingRound, 1049, 100, 10},
+		{"round half", TaxRoundingRound, 1050, 100, 11},
+		{"round negative half", TaxRoundingRound, -1050, 100, -11},
+		{"round negative below half", TaxRoundingRound, -1049, 100, -10},
+		{"round odd denominator", TaxRoundingRound, 10800, 110, 98},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rounding.divide(tt.numerator, tt.denominator)
+			if got != tt.want {
+				t.Errorf("divide(%d, %d) = %d, want %d", tt.numerator, tt.denominator, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTaxBreakdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     TaxRate
+		amount   int
+		mode     TaxMode
+		rounding TaxRounding
+		want     TaxBreakdown
+	}{
+		{
+			name: "exclusive standard floor", rate: TaxRateStandard, amount: 1005, mode: TaxModeExclusive, rounding: TaxRoundingFloor,
+			want: TaxBreakdown{TaxRate: TaxRateStandard, TaxableAmount: 1005, TaxAmount: 100, InclusiveAmount: 1105},
+		},
+		{
+			name: "exclusive standard round", rate: TaxRateStandard, amount: 1005, mode: TaxModeExclusive, rounding: TaxRoundingRound,
+			want: TaxBreakdown{TaxRate: TaxRateStandard, TaxableAmount: 1005, TaxAmount: 101, InclusiveAmount: 1106},
+		},
+		{
+			name: "exclusive reduced ceil", rate: TaxRateReduced, amount: 1001, mode: TaxModeExclusive, rounding: TaxRoundingCeil,
+			want: TaxBreakdown{TaxRate: TaxRateReduced, TaxableAmount: 1001, TaxAmount: 81, InclusiveAmount: 1082},
+		},
+		{
+			name: "inclusive standard floor", rate: TaxRateStandard, amount: 1000, mode: TaxModeInclusive, rounding: TaxRoundingFloor,
+			want: TaxBreakdown{TaxRate: TaxRateStandard, TaxableAmount: 910, TaxAmount: 90, InclusiveAmount: 1000},
+		},
+		{
+			name: "inclusive standard ceil", rate: TaxRateStandard, amount: 1000, mode: TaxModeInclusive, rounding: TaxRoundingCeil,
+			want: TaxBreakdown{TaxRate: TaxRateStandard, TaxableAmount: 909, TaxAmount: 91, InclusiveAmount: 1000},
+		},
+		{
+			name: "inclusive reduced round", rate: TaxRateReduced, amount: 1000, mode: TaxModeInclusive, rounding: TaxRoundingRound,
+			want: TaxBreakdown{TaxRate: TaxRateReduced, TaxableAmount: 926, TaxAmount: 74, InclusiveAmount: 1000},
+		},
+		{
+			name: "inclusive negative floor", rate: TaxRateStandard, amount: -1000, mode: TaxModeInclusive, rounding: TaxRoundingFloor,
+			want: TaxBreakdown{TaxRate: TaxRateStandard, TaxableAmount: -909, TaxAmount: -91, InclusiveAmount: -1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewTaxBreakdown(tt.rate, tt.amount, tt.mode, tt.rounding)
+			if *got != tt.want {
+				t.Errorf("NewTaxBreakdown() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordItemsSetInclusiveAmounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    RecordItems
+		mode     TaxMode
+		rounding TaxRounding
+		want     []int
+	}{
+		{
+			name: "inclusive keeps amounts",
+			items: RecordItems{
+				{Quantity: 2, UnitPrice: 108, TaxRate: TaxRateReduced},
+				{Quantity: 1, UnitPrice: 550, TaxRate: TaxRateStandard},
+			},
+			mode:     TaxModeInclusive,
+			rounding: TaxRoundingFloor,
+			want:     []int{216, 550},
+		},
+		{
+			name: "exclusive allocates remainder to last item of each rate",
+			items: RecordItems{
+				{Quantity: 1, UnitPrice: 105, TaxRate: TaxRateStandard},
+				{Quantity: 1, UnitPrice: 99, TaxRate: TaxRateReduced},
+				{Quantity: 1, UnitPrice: 105, TaxRate: TaxRateStandard},
+			},
+			mode:     TaxModeExclusive,
+			rounding: TaxRoundingRound,
+			// 標準税率: 210 * 10% = 21 を 10 と 11 に、軽減税率: 99 * 8% = 7.92 → 8
+			want: []int{115, 107, 116},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.items.SetInclusiveAmounts(tt.mode, tt.rounding)
+			for i, item := range tt.items {
+				if item.InclusiveAmount != tt.want[i] {
+					t.Errorf("items[%d].InclusiveAmount = %d, want %d", i, item.InclusiveAmount, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+// collectDescendantIDs はrootとその子孫のIDを幅優先で返す。childrenは親のIDから子のIDへの対応
+func collectDescendantIDs[T comparable](root T, children map[T][]T) []T {
+	ids := make([]T, 0)
+	queue := []T{root}
+	seen := make(map[T]struct{})
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		// データが壊れて循環している場合でも無限ループにならないようにする
+		if _, ok := seen[current]; ok {
+			continue
+		}
+		seen[current] = struct{}{}
+		ids = append(ids, current)
+
+		queue = append(queue, children[current]...)
+	}
+
+	return ids
+}
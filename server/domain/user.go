@@ -11,17 +11,19 @@ const (
 type UserID string
 
 type User struct {
-	ID        UserID
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          UserID
+	Name        string
+	TaxRounding TaxRounding // 消費税額の端数処理
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 func NewUser(id UserID, name string) *User {
 	return &User{
-		ID:        id,
-		Name:      name,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          id,
+		Name:        name,
+		TaxRounding: TaxRoundingFloor,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 }
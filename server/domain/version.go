@@ -0,0 +1,13 @@
+package domain
+
+import "golang.org/x/xerrors"
+
+// ValidateVersion は更新対象のバージョンがクライアントの想定するバージョンと一致するかを検証する
+// expectedがnilの場合は検証しない。一致しない場合は、クライアントが読み込んだ後に他の端末から更新されている
+func ValidateVersion(current int, expected *int) error {
+	if expected == nil || *expected == current {
+		return nil
+	}
+
+	return xerrors.Errorf("expected version %d, but current version is %d: %w", *expected, current, ErrConflict)
+}
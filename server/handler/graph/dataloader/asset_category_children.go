@@ -0,0 +1,56 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type assetCategoryChildrenBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (a *assetCategoryChildrenBatcher) BatchGetAssetCategoriesByParentIDs(ctx context.Context, parentIDs []domain.AssetCategoryID) []*dataloader.Result[[]*domain.AssetCategory] {
+	results := make([]*dataloader.Result[[]*domain.AssetCategory], len(parentIDs))
+
+	indexs := make(map[domain.AssetCategoryID]int, len(parentIDs))
+	for i, ID := range parentIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.AssetCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	categories, err := a.usecase.GetAssetCategoriesByParentIDs(ctx, userID, parentIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.AssetCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for _, parentID := range parentIDs {
+		results[indexs[parentID]] = &dataloader.Result[[]*domain.AssetCategory]{
+			Data:  make([]*domain.AssetCategory, 0),
+			Error: nil,
+		}
+	}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			panic("category.ParentID is nil")
+		}
+		results[indexs[*category.ParentID]].Data = append(results[indexs[*category.ParentID]].Data, category)
+	}
+
+	return results
+}
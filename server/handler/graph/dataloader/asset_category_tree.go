@@ -0,0 +1,68 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+// assetCategoryTreeBatcher はカテゴリとその子孫のカテゴリをまとめて扱う値を、カテゴリの一覧を1回だけ取得して求める
+type assetCategoryTreeBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (a *assetCategoryTreeBatcher) BatchGetAssetsByCategoryIDsRecursive(ctx context.Context, assetCategoryIDs []domain.AssetCategoryID) []*dataloader.Result[[]*domain.Asset] {
+	results := make([]*dataloader.Result[[]*domain.Asset], len(assetCategoryIDs))
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.Asset]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	assets, err := a.usecase.GetAssetsByAssetCategoryIDsRecursive(ctx, userID, assetCategoryIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.Asset]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for i, assetCategoryID := range assetCategoryIDs {
+		results[i] = &dataloader.Result[[]*domain.Asset]{Data: assets[assetCategoryID]}
+	}
+
+	return results
+}
+
+func (a *assetCategoryTreeBatcher) BatchGetTotalAmounts(ctx context.Context, assetCategoryIDs []domain.AssetCategoryID) []*dataloader.Result[int] {
+	results := make([]*dataloader.Result[int], len(assetCategoryIDs))
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[int]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	amounts, err := a.usecase.GetAssetCategoryTotalAmounts(ctx, userID, assetCategoryIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[int]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for i, assetCategoryID := range assetCategoryIDs {
+		results[i] = &dataloader.Result[int]{Data: amounts[assetCategoryID]}
+	}
+
+	return results
+}
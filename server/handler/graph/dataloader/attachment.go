@@ -0,0 +1,54 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type attachmentBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (a *attachmentBatcher) BatchGetAttachmentsByRecordIDs(ctx context.Context, recordIDs []domain.RecordID) []*dataloader.Result[[]*domain.Attachment] {
+	results := make([]*dataloader.Result[[]*domain.Attachment], len(recordIDs))
+
+	indexs := make(map[domain.RecordID]int, len(recordIDs))
+	for i, ID := range recordIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.Attachment]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	attachments, err := a.usecase.GetAttachmentsByRecordIDs(ctx, userID, recordIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.Attachment]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for _, recordID := range recordIDs {
+		results[indexs[recordID]] = &dataloader.Result[[]*domain.Attachment]{
+			Data:  make([]*domain.Attachment, 0),
+			Error: nil,
+		}
+	}
+
+	for _, attachment := range attachments {
+		index := indexs[*attachment.RecordID]
+		results[index].Data = append(results[index].Data, attachment)
+	}
+
+	return results
+}
@@ -11,26 +11,52 @@ import (
 )
 
 type Loaders struct {
-	AssetCategoryLoader    dataloader.Interface[domain.AssetCategoryID, *domain.AssetCategory]
-	AssetChangeLoader      dataloader.Interface[domain.RecordID, *AssetChangesAssociation]
-	AssetLoader            dataloader.Interface[domain.AssetID, *domain.Asset]
-	AssetsByCategoryLoader dataloader.Interface[domain.AssetCategoryID, []*domain.Asset]
-	TagLoader              dataloader.Interface[domain.RecordID, []*domain.Tag]
+	AssetCategoryLoader          dataloader.Interface[domain.AssetCategoryID, *domain.AssetCategory]
+	AssetCategoryChildrenLoader  dataloader.Interface[domain.AssetCategoryID, []*domain.AssetCategory]
+	AssetChangeLoader            dataloader.Interface[domain.RecordID, *AssetChangesAssociation]
+	AssetLoader                  dataloader.Interface[domain.AssetID, *domain.Asset]
+	AssetsByCategoryLoader       dataloader.Interface[domain.AssetCategoryID, []*domain.Asset]
+	AssetsByCategoryTreeLoader   dataloader.Interface[domain.AssetCategoryID, []*domain.Asset]
+	AssetCategoryAmountLoader    dataloader.Interface[domain.AssetCategoryID, int]
+	TagLoader                    dataloader.Interface[domain.RecordID, []*domain.Tag]
+	TagStatLoader                dataloader.Interface[domain.TagID, *domain.TagStat]
+	RecordCategoryLoader         dataloader.Interface[domain.RecordCategoryID, *domain.RecordCategory]
+	RecordCategoryChildrenLoader dataloader.Interface[domain.RecordCategoryID, []*domain.RecordCategory]
+	PayeeLoader                  dataloader.Interface[domain.PayeeID, *domain.Payee]
+	AttachmentLoader             dataloader.Interface[domain.RecordID, []*domain.Attachment]
+	RecordItemLoader             dataloader.Interface[domain.RecordID, []*domain.RecordItem]
 }
 
 func NewLoader(usecase *usecase.Usecase) *Loaders {
 	assetCategoryBatcher := &assetCategoryBatcher{usecase: usecase}
+	assetCategoryChildrenBatcher := &assetCategoryChildrenBatcher{usecase: usecase}
 	assetChangeBatcher := &assetChangeBatcher{usecase: usecase}
 	assetBatcher := &assetBatcher{usecase: usecase}
 	assetsByCategoryBatcher := &assetsByCategoryBatcher{usecase: usecase}
+	assetCategoryTreeBatcher := &assetCategoryTreeBatcher{usecase: usecase}
 	tagBatcher := &tagBatcher{usecase: usecase}
+	tagStatBatcher := &tagStatBatcher{usecase: usecase}
+	recordCategoryBatcher := &recordCategoryBatcher{usecase: usecase}
+	recordCategoryChildrenBatcher := &recordCategoryChildrenBatcher{usecase: usecase}
+	payeeBatcher := &payeeBatcher{usecase: usecase}
+	attachmentBatcher := &attachmentBatcher{usecase: usecase}
+	recordItemBatcher := &recordItemBatcher{usecase: usecase}
 
 	return &Loaders{
-		AssetCategoryLoader:    dataloader.NewBatchedLoader(assetCategoryBatcher.BatchGetAssetCategories),
-		AssetChangeLoader:      dataloader.NewBatchedLoader(assetChangeBatcher.BatchGetAssetChanges),
-		AssetLoader:            dataloader.NewBatchedLoader(assetBatcher.BatchGetAssets),
-		AssetsByCategoryLoader: dataloader.NewBatchedLoader(assetsByCategoryBatcher.BatchGetAssetsByCategoryIDs),
-		TagLoader:              dataloader.NewBatchedLoader(tagBatcher.BatchGetTagsByRecordIDs),
+		AssetCategoryLoader:          dataloader.NewBatchedLoader(assetCategoryBatcher.BatchGetAssetCategories),
+		AssetCategoryChildrenLoader:  dataloader.NewBatchedLoader(assetCategoryChildrenBatcher.BatchGetAssetCategoriesByParentIDs),
+		AssetChangeLoader:            dataloader.NewBatchedLoader(assetChangeBatcher.BatchGetAssetChanges),
+		AssetLoader:                  dataloader.NewBatchedLoader(assetBatcher.BatchGetAssets),
+		AssetsByCategoryLoader:       dataloader.NewBatchedLoader(assetsByCategoryBatcher.BatchGetAssetsByCategoryIDs),
+		AssetsByCategoryTreeLoader:   dataloader.NewBatchedLoader(assetCategoryTreeBatcher.BatchGetAssetsByCategoryIDsRecursive),
+		AssetCategoryAmountLoader:    dataloader.NewBatchedLoader(assetCategoryTreeBatcher.BatchGetTotalAmounts),
+		TagLoader:                    dataloader.NewBatchedLoader(tagBatcher.BatchGetTagsByRecordIDs),
+		TagStatLoader:                dataloader.NewBatchedLoader(tagStatBatcher.BatchGetTagStats),
+		RecordCategoryLoader:         dataloader.NewBatchedLoader(recordCategoryBatcher.BatchGetRecordCategories),
+		RecordCategoryChildrenLoader: dataloader.NewBatchedLoader(recordCategoryChildrenBatcher.BatchGetRecordCategoriesByParentIDs),
+		PayeeLoader:                  dataloader.NewBatchedLoader(payeeBatcher.BatchGetPayees),
+		AttachmentLoader:             dataloader.NewBatchedLoader(attachmentBatcher.BatchGetAttachmentsByRecordIDs),
+		RecordItemLoader:             dataloader.NewBatchedLoader(recordItemBatcher.BatchGetRecordItemsByRecordIDs),
 	}
 }
 
@@ -0,0 +1,53 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type payeeBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (p *payeeBatcher) BatchGetPayees(ctx context.Context, payeeIDs []domain.PayeeID) []*dataloader.Result[*domain.Payee] {
+	results := make([]*dataloader.Result[*domain.Payee], len(payeeIDs))
+
+	indexs := make(map[domain.PayeeID]int, len(payeeIDs))
+	for i, ID := range payeeIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.Payee]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	payees, err := p.usecase.GetPayeesByIDs(ctx, userID, payeeIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.Payee]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for i := range results {
+		results[i] = &dataloader.Result[*domain.Payee]{Error: domain.ErrEntityNotFound}
+	}
+
+	for _, payee := range payees {
+		results[indexs[payee.ID]] = &dataloader.Result[*domain.Payee]{
+			Data:  payee,
+			Error: nil,
+		}
+	}
+
+	return results
+}
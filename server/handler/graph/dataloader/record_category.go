@@ -0,0 +1,98 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type recordCategoryBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (r *recordCategoryBatcher) BatchGetRecordCategories(ctx context.Context, recordCategoryIDs []domain.RecordCategoryID) []*dataloader.Result[*domain.RecordCategory] {
+	results := make([]*dataloader.Result[*domain.RecordCategory], len(recordCategoryIDs))
+
+	indexs := make(map[domain.RecordCategoryID]int, len(recordCategoryIDs))
+	for i, ID := range recordCategoryIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.RecordCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	categories, err := r.usecase.GetRecordCategoriesByIDs(ctx, userID, recordCategoryIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.RecordCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for i := range results {
+		results[i] = &dataloader.Result[*domain.RecordCategory]{Error: domain.ErrEntityNotFound}
+	}
+
+	for _, category := range categories {
+		results[indexs[category.ID]] = &dataloader.Result[*domain.RecordCategory]{
+			Data:  category,
+			Error: nil,
+		}
+	}
+
+	return results
+}
+
+type recordCategoryChildrenBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (r *recordCategoryChildrenBatcher) BatchGetRecordCategoriesByParentIDs(ctx context.Context, parentIDs []domain.RecordCategoryID) []*dataloader.Result[[]*domain.RecordCategory] {
+	results := make([]*dataloader.Result[[]*domain.RecordCategory], len(parentIDs))
+
+	indexs := make(map[domain.RecordCategoryID]int, len(parentIDs))
+	for i, ID := range parentIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.RecordCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	categories, err := r.usecase.GetRecordCategoriesByParentIDs(ctx, userID, parentIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.RecordCategory]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for _, parentID := range parentIDs {
+		results[indexs[parentID]] = &dataloader.Result[[]*domain.RecordCategory]{
+			Data:  make([]*domain.RecordCategory, 0),
+			Error: nil,
+		}
+	}
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			panic("category.ParentID is nil")
+		}
+		results[indexs[*category.ParentID]].Data = append(results[indexs[*category.ParentID]].Data, category)
+	}
+
+	return results
+}
@@ -0,0 +1,54 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type recordItemBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (r *recordItemBatcher) BatchGetRecordItemsByRecordIDs(ctx context.Context, recordIDs []domain.RecordID) []*dataloader.Result[[]*domain.RecordItem] {
+	results := make([]*dataloader.Result[[]*domain.RecordItem], len(recordIDs))
+
+	indexs := make(map[domain.RecordID]int, len(recordIDs))
+	for i, ID := range recordIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.RecordItem]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	items, err := r.usecase.GetRecordItemsByRecordIDs(ctx, userID, recordIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[[]*domain.RecordItem]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	for _, recordID := range recordIDs {
+		results[indexs[recordID]] = &dataloader.Result[[]*domain.RecordItem]{
+			Data:  make([]*domain.RecordItem, 0),
+			Error: nil,
+		}
+	}
+
+	for _, item := range items {
+		index := indexs[item.RecordID]
+		results[index].Data = append(results[index].Data, item)
+	}
+
+	return results
+}
@@ -0,0 +1,54 @@
+package dataloader
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/usecase"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"golang.org/x/xerrors"
+)
+
+type tagStatBatcher struct {
+	usecase *usecase.Usecase
+}
+
+func (t *tagStatBatcher) BatchGetTagStats(ctx context.Context, tagIDs []domain.TagID) []*dataloader.Result[*domain.TagStat] {
+	results := make([]*dataloader.Result[*domain.TagStat], len(tagIDs))
+
+	indexs := make(map[domain.TagID]int, len(tagIDs))
+	for i, ID := range tagIDs {
+		indexs[ID] = i
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.TagStat]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	stats, err := t.usecase.GetTagStatsByTagIDs(ctx, userID, tagIDs)
+	if err != nil {
+		for i := range results {
+			results[i] = &dataloader.Result[*domain.TagStat]{Error: xerrors.Errorf(": %w", err)}
+		}
+		return results
+	}
+
+	// 未使用のTagは集計結果に含まれないため、件数0で初期化しておく
+	for _, tagID := range tagIDs {
+		results[indexs[tagID]] = &dataloader.Result[*domain.TagStat]{
+			Data:  &domain.TagStat{TagID: tagID},
+			Error: nil,
+		}
+	}
+
+	for _, stat := range stats {
+		results[indexs[stat.TagID]].Data = stat
+	}
+
+	return results
+}
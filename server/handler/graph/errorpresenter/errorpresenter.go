@@ -0,0 +1,29 @@
+package errorpresenter
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// CodeConflict は他の端末で先に更新されたため、更新できなかったことを表す
+	CodeConflict = "CONFLICT"
+)
+
+// Present はクライアントがエラーの種類で処理を分けられるよう、extensions.codeにエラーコードを設定する
+func Present(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	if errors.Is(err, domain.ErrConflict) {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]any)
+		}
+		gqlErr.Extensions["code"] = CodeConflict
+	}
+
+	return gqlErr
+}
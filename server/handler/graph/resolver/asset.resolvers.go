@@ -35,6 +35,21 @@ func (r *assetResolver) Category(ctx context.Context, obj *domain.Asset) (*domai
 	return category, nil
 }
 
+// TotalCount is the resolver for the totalCount field.
+func (r *assetConnectionResolver) TotalCount(ctx context.Context, obj *domain.AssetConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	count, err := r.usecase.CountAssetsByUserIDAndCategoryID(ctx, userID, obj.CategoryID, obj.IncludeArchived)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
 // CreateAsset is the resolver for the createAsset field.
 func (r *mutationResolver) CreateAsset(ctx context.Context, input domain.CreateAssetInput) (*domain.Asset, error) {
 	userID, err := ctxdef.UserID(ctx)
@@ -47,7 +62,7 @@ func (r *mutationResolver) CreateAsset(ctx context.Context, input domain.CreateA
 		categoryID = typeutil.Ptr(domain.AssetCategoryID(*input.CategoryID))
 	}
 
-	asset, err := r.usecase.CreateAsset(ctx, userID, input.Name, categoryID)
+	asset, err := r.usecase.CreateAsset(ctx, userID, input.Name, categoryID, input.IdempotencyKey)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -67,7 +82,7 @@ func (r *mutationResolver) UpdateAsset(ctx context.Context, input domain.UpdateA
 		assetCategoryID = typeutil.Ptr(domain.AssetCategoryID(*input.CategoryID))
 	}
 
-	asset, err := r.usecase.UpdateAsset(ctx, userID, domain.AssetID(input.ID), input.Name, assetCategoryID)
+	asset, err := r.usecase.UpdateAsset(ctx, userID, domain.AssetID(input.ID), input.Name, assetCategoryID, input.ExpectedVersion)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -75,13 +90,13 @@ func (r *mutationResolver) UpdateAsset(ctx context.Context, input domain.UpdateA
 }
 
 // DeleteAsset is the resolver for the deleteAsset field.
-func (r *mutationResolver) DeleteAsset(ctx context.Context, id string) (*domain.Asset, error) {
+func (r *mutationResolver) DeleteAsset(ctx context.Context, id string, cascade bool) (*domain.Asset, error) {
 	userID, err := ctxdef.UserID(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	assetID, err := r.usecase.DeleteAsset(ctx, userID, domain.AssetID(id))
+	assetID, err := r.usecase.DeleteAsset(ctx, userID, domain.AssetID(id), cascade)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -91,8 +106,58 @@ func (r *mutationResolver) DeleteAsset(ctx context.Context, id string) (*domain.
 	}, nil
 }
 
+// ArchiveAsset is the resolver for the archiveAsset field.
+func (r *mutationResolver) ArchiveAsset(ctx context.Context, id string) (*domain.Asset, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	asset, err := r.usecase.SetAssetArchived(ctx, userID, domain.AssetID(id), true)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return asset, nil
+}
+
+// UnarchiveAsset is the resolver for the unarchiveAsset field.
+func (r *mutationResolver) UnarchiveAsset(ctx context.Context, id string) (*domain.Asset, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	asset, err := r.usecase.SetAssetArchived(ctx, userID, domain.AssetID(id), false)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return asset, nil
+}
+
+// ReorderAssets is the resolver for the reorderAssets field.
+func (r *mutationResolver) ReorderAssets(ctx context.Context, ids []string) ([]*domain.Asset, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	assetIDs := make([]domain.AssetID, 0, len(ids))
+	for _, id := range ids {
+		assetIDs = append(assetIDs, domain.AssetID(id))
+	}
+
+	assets, err := r.usecase.ReorderAssets(ctx, userID, assetIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return assets, nil
+}
+
 // Assets is the resolver for the assets field.
-func (r *queryResolver) Assets(ctx context.Context, categoryID *string, sortKey domain.AssetSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.AssetConnection, error) {
+func (r *queryResolver) Assets(ctx context.Context, categoryID *string, includeArchived bool, sortKey domain.AssetSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.AssetConnection, error) {
 	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortKey))
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -108,18 +173,26 @@ func (r *queryResolver) Assets(ctx context.Context, categoryID *string, sortKey
 		categoryIDPtr = typeutil.Ptr(domain.AssetCategoryID(*categoryID))
 	}
 
-	assets, pageInfo, err := r.usecase.GetAssetsByUserIDAndCategoryID(ctx, pageParam, userID, categoryIDPtr)
+	assets, pageInfo, err := r.usecase.GetAssetsByUserIDAndCategoryID(ctx, pageParam, userID, categoryIDPtr, includeArchived)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
 	return &domain.AssetConnection{
-		Nodes:    assets,
-		PageInfo: pageInfo,
+		Nodes:           assets,
+		PageInfo:        pageInfo,
+		CategoryID:      categoryIDPtr,
+		IncludeArchived: includeArchived,
 	}, nil
 }
 
 // Asset returns graph.AssetResolver implementation.
 func (r *Resolver) Asset() graph.AssetResolver { return &assetResolver{r} }
 
+// AssetConnection returns graph.AssetConnectionResolver implementation.
+func (r *Resolver) AssetConnection() graph.AssetConnectionResolver {
+	return &assetConnectionResolver{r}
+}
+
 type assetResolver struct{ *Resolver }
+type assetConnectionResolver struct{ *Resolver }
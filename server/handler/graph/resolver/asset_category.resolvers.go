@@ -10,6 +10,7 @@ import (
 	"kakeibo-web-server/domain"
 	"kakeibo-web-server/handler/graph"
 	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/lib/typeutil"
 )
 
 // ID is the resolver for the id field.
@@ -17,8 +18,47 @@ func (r *assetCategoryResolver) ID(ctx context.Context, obj *domain.AssetCategor
 	return string(obj.ID), nil
 }
 
+// Parent is the resolver for the parent field.
+func (r *assetCategoryResolver) Parent(ctx context.Context, obj *domain.AssetCategory) (*domain.AssetCategory, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	thunk := r.Loaders.AssetCategoryLoader.Load(ctx, *obj.ParentID)
+
+	parent, err := thunk()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent for category %s: %w", obj.ID, err)
+	}
+
+	return parent, nil
+}
+
+// Children is the resolver for the children field.
+func (r *assetCategoryResolver) Children(ctx context.Context, obj *domain.AssetCategory) ([]*domain.AssetCategory, error) {
+	thunk := r.Loaders.AssetCategoryChildrenLoader.Load(ctx, obj.ID)
+
+	children, err := thunk()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load children for category %s: %w", obj.ID, err)
+	}
+
+	return children, nil
+}
+
 // Assets is the resolver for the assets field.
-func (r *assetCategoryResolver) Assets(ctx context.Context, obj *domain.AssetCategory) ([]*domain.Asset, error) {
+func (r *assetCategoryResolver) Assets(ctx context.Context, obj *domain.AssetCategory, recursive bool) ([]*domain.Asset, error) {
+	if recursive {
+		thunk := r.Loaders.AssetsByCategoryTreeLoader.Load(ctx, obj.ID)
+
+		assets, err := thunk()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load assets for category %s recursively: %w", obj.ID, err)
+		}
+
+		return assets, nil
+	}
+
 	thunk := r.Loaders.AssetsByCategoryLoader.Load(ctx, obj.ID)
 
 	assets, err := thunk()
@@ -29,6 +69,33 @@ func (r *assetCategoryResolver) Assets(ctx context.Context, obj *domain.AssetCat
 	return assets, nil
 }
 
+// TotalAmount is the resolver for the totalAmount field.
+func (r *assetCategoryResolver) TotalAmount(ctx context.Context, obj *domain.AssetCategory) (int, error) {
+	thunk := r.Loaders.AssetCategoryAmountLoader.Load(ctx, obj.ID)
+
+	amount, err := thunk()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load total amount for category %s: %w", obj.ID, err)
+	}
+
+	return amount, nil
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *assetCategoryConnectionResolver) TotalCount(ctx context.Context, obj *domain.AssetCategoryConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+
+	count, err := r.usecase.CountAssetCategoriesByUserID(ctx, userID, obj.RootOnly)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count asset categories: %w", err)
+	}
+
+	return count, nil
+}
+
 // CreateAssetCategory is the resolver for the createAssetCategory field.
 func (r *mutationResolver) CreateAssetCategory(ctx context.Context, input domain.CreateAssetCategoryInput) (*domain.AssetCategory, error) {
 	userID, err := ctxdef.UserID(ctx)
@@ -36,7 +103,12 @@ func (r *mutationResolver) CreateAssetCategory(ctx context.Context, input domain
 		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
 	}
 
-	assetCategory, err := r.usecase.CreateAssetCategory(ctx, userID, input.Name)
+	var parentID *domain.AssetCategoryID
+	if input.ParentID != nil {
+		parentID = typeutil.Ptr(domain.AssetCategoryID(*input.ParentID))
+	}
+
+	assetCategory, err := r.usecase.CreateAssetCategory(ctx, userID, input.Name, parentID, input.IdempotencyKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create asset category: %w", err)
 	}
@@ -59,25 +131,51 @@ func (r *mutationResolver) UpdateAssetCategory(ctx context.Context, input domain
 	return assetCategory, nil
 }
 
+// MoveAssetCategory is the resolver for the moveAssetCategory field.
+func (r *mutationResolver) MoveAssetCategory(ctx context.Context, input domain.MoveAssetCategoryInput) (*domain.AssetCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
+	}
+
+	var parentID *domain.AssetCategoryID
+	if input.ParentID != nil {
+		parentID = typeutil.Ptr(domain.AssetCategoryID(*input.ParentID))
+	}
+
+	assetCategory, err := r.usecase.MoveAssetCategory(ctx, userID, domain.AssetCategoryID(input.ID), parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move asset category: %w", err)
+	}
+
+	return assetCategory, nil
+}
+
 // DeleteAssetCategory is the resolver for the deleteAssetCategory field.
-func (r *mutationResolver) DeleteAssetCategory(ctx context.Context, input domain.DeleteAssetCategoryInput) (*domain.AssetCategory, error) {
+func (r *mutationResolver) DeleteAssetCategory(ctx context.Context, input domain.DeleteAssetCategoryInput) (*domain.DeleteAssetCategoryPayload, error) {
 	userID, err := ctxdef.UserID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
 	}
 
-	assetCategoryID, err := r.usecase.DeleteAssetCategory(ctx, userID, domain.AssetCategoryID(input.ID))
+	var moveTo *domain.AssetCategoryID
+	if input.MoveTo != nil {
+		moveTo = typeutil.Ptr(domain.AssetCategoryID(*input.MoveTo))
+	}
+
+	assetCategoryID, affectedCount, err := r.usecase.DeleteAssetCategory(ctx, userID, domain.AssetCategoryID(input.ID), input.ReparentChildren, input.Strategy, moveTo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete asset category: %w", err)
 	}
 
-	return &domain.AssetCategory{
-		ID: assetCategoryID,
+	return &domain.DeleteAssetCategoryPayload{
+		ID:                 string(assetCategoryID),
+		AffectedAssetCount: affectedCount,
 	}, nil
 }
 
 // AssetCategories is the resolver for the assetCategories field.
-func (r *queryResolver) AssetCategories(ctx context.Context, sortKey domain.AssetCategorySortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.AssetCategoryConnection, error) {
+func (r *queryResolver) AssetCategories(ctx context.Context, nested bool, sortKey domain.AssetCategorySortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.AssetCategoryConnection, error) {
 	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page param: %w", err)
@@ -88,7 +186,7 @@ func (r *queryResolver) AssetCategories(ctx context.Context, sortKey domain.Asse
 		return nil, fmt.Errorf("failed to get user ID from context: %w", err)
 	}
 
-	categories, pageInfo, err := r.usecase.GetAssetCategoriesByUserID(ctx, pageParam, userID)
+	categories, pageInfo, err := r.usecase.GetAssetCategoriesByUserID(ctx, pageParam, userID, nested)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get asset categories by user ID: %w", err)
 	}
@@ -96,10 +194,17 @@ func (r *queryResolver) AssetCategories(ctx context.Context, sortKey domain.Asse
 	return &domain.AssetCategoryConnection{
 		Nodes:    categories,
 		PageInfo: pageInfo,
+		RootOnly: nested,
 	}, nil
 }
 
 // AssetCategory returns graph.AssetCategoryResolver implementation.
 func (r *Resolver) AssetCategory() graph.AssetCategoryResolver { return &assetCategoryResolver{r} }
 
+// AssetCategoryConnection returns graph.AssetCategoryConnectionResolver implementation.
+func (r *Resolver) AssetCategoryConnection() graph.AssetCategoryConnectionResolver {
+	return &assetCategoryConnectionResolver{r}
+}
+
 type assetCategoryResolver struct{ *Resolver }
+type assetCategoryConnectionResolver struct{ *Resolver }
@@ -0,0 +1,90 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"github.com/99designs/gqlgen/graphql"
+	"golang.org/x/xerrors"
+)
+
+// ID is the resolver for the id field.
+func (r *attachmentResolver) ID(ctx context.Context, obj *domain.Attachment) (string, error) {
+	return string(obj.ID), nil
+}
+
+// URL is the resolver for the url field.
+func (r *attachmentResolver) URL(ctx context.Context, obj *domain.Attachment) (string, error) {
+	url, err := r.usecase.GetAttachmentURL(ctx, obj)
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
+	}
+
+	return url, nil
+}
+
+// UploadAttachment is the resolver for the uploadAttachment field.
+func (r *mutationResolver) UploadAttachment(ctx context.Context, recordID string, file graphql.Upload) (*domain.Attachment, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	attachment, err := r.usecase.UploadAttachment(ctx, userID, domain.RecordID(recordID), file.Filename, int(file.Size), file.File)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachment, nil
+}
+
+// DeleteAttachment is the resolver for the deleteAttachment field.
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, id string) (*domain.Attachment, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	attachment, err := r.usecase.DeleteAttachment(ctx, userID, domain.AttachmentID(id))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachment, nil
+}
+
+// AttachmentUsage is the resolver for the attachmentUsage field.
+func (r *queryResolver) AttachmentUsage(ctx context.Context) (*domain.AttachmentUsage, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	usage, err := r.usecase.GetAttachmentUsage(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return usage, nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *recordResolver) Attachments(ctx context.Context, obj *domain.Record) ([]*domain.Attachment, error) {
+	attachments, err := r.Loaders.AttachmentLoader.Load(ctx, obj.ID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Attachment returns graph.AttachmentResolver implementation.
+func (r *Resolver) Attachment() graph.AttachmentResolver { return &attachmentResolver{r} }
+
+type attachmentResolver struct{ *Resolver }
@@ -0,0 +1,73 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"golang.org/x/xerrors"
+)
+
+// SetTagBusinessRatio is the resolver for the setTagBusinessRatio field.
+func (r *mutationResolver) SetTagBusinessRatio(ctx context.Context, id string, ratio *int, expectedVersion *int) (*domain.Tag, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	tag, err := r.usecase.SetTagBusinessRatio(ctx, userID, domain.TagID(id), ratio, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tag, nil
+}
+
+// SetAssetBusinessRatio is the resolver for the setAssetBusinessRatio field.
+func (r *mutationResolver) SetAssetBusinessRatio(ctx context.Context, id string, ratio *int, expectedVersion *int) (*domain.Asset, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	asset, err := r.usecase.SetAssetBusinessRatio(ctx, userID, domain.AssetID(id), ratio, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return asset, nil
+}
+
+// SetRecordCategoryExpenseAccount is the resolver for the setRecordCategoryExpenseAccount field.
+func (r *mutationResolver) SetRecordCategoryExpenseAccount(ctx context.Context, id string, account *domain.ExpenseAccount) (*domain.RecordCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	category, err := r.usecase.SetRecordCategoryExpenseAccount(ctx, userID, domain.RecordCategoryID(id), account)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+// BusinessExpenseReport is the resolver for the businessExpenseReport field.
+func (r *queryResolver) BusinessExpenseReport(ctx context.Context, year int) (*domain.BusinessExpenseReport, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	report, err := r.usecase.GetBusinessExpenseReport(ctx, userID, year)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,63 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/lib/typeutil"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// RecordID is the resolver for the recordID field.
+func (r *cashFlowEventResolver) RecordID(ctx context.Context, obj *domain.CashFlowEvent) (*string, error) {
+	if obj.RecordID == nil {
+		return nil, nil
+	}
+
+	return typeutil.Ptr(string(*obj.RecordID)), nil
+}
+
+// Asset is the resolver for the asset field.
+func (r *cashFlowForecastResolver) Asset(ctx context.Context, obj *domain.CashFlowForecast) (*domain.Asset, error) {
+	thunk := r.Loaders.AssetLoader.Load(ctx, obj.AssetID)
+
+	asset, err := thunk()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return asset, nil
+}
+
+// CashFlowForecast is the resolver for the cashFlowForecast field.
+func (r *queryResolver) CashFlowForecast(ctx context.Context, assetID string, until time.Time, threshold int, historyMonths int) (*domain.CashFlowForecast, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	forecast, err := r.usecase.GetCashFlowForecast(ctx, userID, domain.AssetID(assetID), until, threshold, historyMonths)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return forecast, nil
+}
+
+// CashFlowEvent returns graph.CashFlowEventResolver implementation.
+func (r *Resolver) CashFlowEvent() graph.CashFlowEventResolver { return &cashFlowEventResolver{r} }
+
+// CashFlowForecast returns graph.CashFlowForecastResolver implementation.
+func (r *Resolver) CashFlowForecast() graph.CashFlowForecastResolver {
+	return &cashFlowForecastResolver{r}
+}
+
+type cashFlowEventResolver struct{ *Resolver }
+type cashFlowForecastResolver struct{ *Resolver }
@@ -0,0 +1,126 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"golang.org/x/xerrors"
+)
+
+// ID is the resolver for the id field.
+func (r *goalResolver) ID(ctx context.Context, obj *domain.Goal) (string, error) {
+	return string(obj.ID), nil
+}
+
+// Assets is the resolver for the assets field.
+func (r *goalResolver) Assets(ctx context.Context, obj *domain.Goal) ([]*domain.Asset, error) {
+	assets, errs := r.Loaders.AssetLoader.LoadMany(ctx, obj.AssetIDs)()
+	for _, err := range errs {
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+
+	return assets, nil
+}
+
+// Progress is the resolver for the progress field.
+func (r *goalResolver) Progress(ctx context.Context, obj *domain.Goal) (*domain.GoalProgress, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	progress, err := r.usecase.GetGoalProgress(ctx, userID, obj)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return progress, nil
+}
+
+// CreateGoal is the resolver for the createGoal field.
+func (r *mutationResolver) CreateGoal(ctx context.Context, input domain.CreateGoalInput) (*domain.Goal, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	assetIDs := make([]domain.AssetID, 0, len(input.AssetIDs))
+	for _, id := range input.AssetIDs {
+		assetIDs = append(assetIDs, domain.AssetID(id))
+	}
+
+	goal, err := r.usecase.CreateGoal(ctx, userID, input.Name, input.TargetAmount, input.TargetDate, assetIDs, input.IdempotencyKey)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+// UpdateGoal is the resolver for the updateGoal field.
+func (r *mutationResolver) UpdateGoal(ctx context.Context, input domain.UpdateGoalInput) (*domain.Goal, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	assetIDs := make([]domain.AssetID, 0, len(input.AssetIDs))
+	for _, id := range input.AssetIDs {
+		assetIDs = append(assetIDs, domain.AssetID(id))
+	}
+
+	goal, err := r.usecase.UpdateGoal(ctx, userID, domain.GoalID(input.ID), input.Name, input.TargetAmount, input.TargetDate, assetIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+// DeleteGoal is the resolver for the deleteGoal field.
+func (r *mutationResolver) DeleteGoal(ctx context.Context, id string) (*domain.Goal, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	goal, err := r.usecase.GetGoalByID(ctx, userID, domain.GoalID(id))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	_, err = r.usecase.DeleteGoal(ctx, userID, goal.ID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+// Goals is the resolver for the goals field.
+func (r *queryResolver) Goals(ctx context.Context) ([]*domain.Goal, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	goals, err := r.usecase.GetGoals(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goals, nil
+}
+
+// Goal returns graph.GoalResolver implementation.
+func (r *Resolver) Goal() graph.GoalResolver { return &goalResolver{r} }
+
+type goalResolver struct{ *Resolver }
@@ -0,0 +1,74 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Payee is the resolver for the payee field.
+func (r *itemPayeePriceStatResolver) Payee(ctx context.Context, obj *domain.ItemPayeePriceStat) (*domain.Payee, error) {
+	if obj.PayeeID == nil {
+		return nil, nil
+	}
+
+	payee, err := r.Loaders.PayeeLoader.Load(ctx, *obj.PayeeID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// RecordID is the resolver for the recordID field.
+func (r *itemPriceResolver) RecordID(ctx context.Context, obj *domain.ItemPrice) (string, error) {
+	return string(obj.RecordID), nil
+}
+
+// Payee is the resolver for the payee field.
+func (r *itemPriceResolver) Payee(ctx context.Context, obj *domain.ItemPrice) (*domain.Payee, error) {
+	if obj.PayeeID == nil {
+		return nil, nil
+	}
+
+	payee, err := r.Loaders.PayeeLoader.Load(ctx, *obj.PayeeID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// ItemPriceHistories is the resolver for the itemPriceHistories field.
+func (r *queryResolver) ItemPriceHistories(ctx context.Context, query string, from time.Time, to time.Time) ([]*domain.ItemPriceHistory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	histories, err := r.usecase.GetItemPriceHistories(ctx, userID, query, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return histories, nil
+}
+
+// ItemPayeePriceStat returns graph.ItemPayeePriceStatResolver implementation.
+func (r *Resolver) ItemPayeePriceStat() graph.ItemPayeePriceStatResolver {
+	return &itemPayeePriceStatResolver{r}
+}
+
+// ItemPrice returns graph.ItemPriceResolver implementation.
+func (r *Resolver) ItemPrice() graph.ItemPriceResolver { return &itemPriceResolver{r} }
+
+type itemPayeePriceStatResolver struct{ *Resolver }
+type itemPriceResolver struct{ *Resolver }
@@ -0,0 +1,48 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"golang.org/x/xerrors"
+)
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id string) (domain.Node, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	nodes, err := r.usecase.GetNodesByIDs(ctx, userID, []domain.ID{domain.ID(id)})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return nodes[0], nil
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]domain.Node, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	nodeIDs := make([]domain.ID, 0, len(ids))
+	for _, id := range ids {
+		nodeIDs = append(nodeIDs, domain.ID(id))
+	}
+
+	nodes, err := r.usecase.GetNodesByIDs(ctx, userID, nodeIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,145 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CreatePayee is the resolver for the createPayee field.
+func (r *mutationResolver) CreatePayee(ctx context.Context, input domain.CreatePayeeInput) (*domain.Payee, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payee, err := r.usecase.CreatePayee(ctx, userID, input.Name, input.Aliases, input.IdempotencyKey)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// UpdatePayee is the resolver for the updatePayee field.
+func (r *mutationResolver) UpdatePayee(ctx context.Context, input domain.UpdatePayeeInput) (*domain.Payee, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payee, err := r.usecase.UpdatePayee(ctx, userID, domain.PayeeID(input.ID), input.Name, input.Aliases)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// DeletePayee is the resolver for the deletePayee field.
+func (r *mutationResolver) DeletePayee(ctx context.Context, id string) (*domain.Payee, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payeeID, err := r.usecase.DeletePayee(ctx, userID, domain.PayeeID(id))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.Payee{
+		ID:      payeeID,
+		Aliases: make([]string, 0),
+	}, nil
+}
+
+// ID is the resolver for the id field.
+func (r *payeeResolver) ID(ctx context.Context, obj *domain.Payee) (string, error) {
+	return string(obj.ID), nil
+}
+
+// Records is the resolver for the records field.
+func (r *payeeResolver) Records(ctx context.Context, obj *domain.Payee, sortKey domain.RecordSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.RecordConnection, error) {
+	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortKey))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	cond := &domain.RecordCondition{PayeeIDs: []domain.PayeeID{obj.ID}}
+
+	records, pageInfo, err := r.usecase.GetRecordsByCondition(ctx, pageParam, userID, cond)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	// 一部のRecordだけでは資産合計を計算できないため、totalAssetsは0にする
+	return &domain.RecordConnection{
+		Nodes:     records,
+		PageInfo:  pageInfo,
+		Condition: cond,
+	}, nil
+}
+
+// Payee is the resolver for the payee field.
+func (r *queryResolver) Payee(ctx context.Context, id string) (*domain.Payee, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payee, err := r.usecase.GetPayeeByID(ctx, userID, domain.PayeeID(id))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// Payees is the resolver for the payees field.
+func (r *queryResolver) Payees(ctx context.Context) ([]*domain.Payee, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payees, err := r.usecase.GetPayees(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees, nil
+}
+
+// PayeeSpending is the resolver for the payeeSpending field.
+func (r *queryResolver) PayeeSpending(ctx context.Context, from time.Time, to time.Time) ([]*domain.PayeeSpending, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	spendings, err := r.usecase.GetPayeeSpending(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return spendings, nil
+}
+
+// Payee returns graph.PayeeResolver implementation.
+func (r *Resolver) Payee() graph.PayeeResolver { return &payeeResolver{r} }
+
+type payeeResolver struct{ *Resolver }
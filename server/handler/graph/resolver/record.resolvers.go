@@ -34,7 +34,17 @@ func (r *mutationResolver) CreateIncomeRecord(ctx context.Context, input domain.
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, _, err := r.usecase.CreateIncomeRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags)
+	var categoryID *domain.RecordCategoryID
+	if input.CategoryID != nil {
+		categoryID = typeutil.Ptr(domain.RecordCategoryID(*input.CategoryID))
+	}
+
+	var payeeID *domain.PayeeID
+	if input.PayeeID != nil {
+		payeeID = typeutil.Ptr(domain.PayeeID(*input.PayeeID))
+	}
+
+	record, _, err := r.usecase.CreateIncomeRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags, categoryID, payeeID, input.TaxRate, input.IdempotencyKey)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -49,7 +59,17 @@ func (r *mutationResolver) CreateExpenseRecord(ctx context.Context, input domain
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, _, err := r.usecase.CreateExpenseRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags)
+	var categoryID *domain.RecordCategoryID
+	if input.CategoryID != nil {
+		categoryID = typeutil.Ptr(domain.RecordCategoryID(*input.CategoryID))
+	}
+
+	var payeeID *domain.PayeeID
+	if input.PayeeID != nil {
+		payeeID = typeutil.Ptr(domain.PayeeID(*input.PayeeID))
+	}
+
+	record, _, err := r.usecase.CreateExpenseRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags, categoryID, payeeID, input.TaxRate, input.IdempotencyKey)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -64,7 +84,7 @@ func (r *mutationResolver) CreateTransferRecord(ctx context.Context, input domai
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, _, _, err := r.usecase.CreateTransferRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.FromAssetID), domain.AssetID(input.ToAssetID), input.Amount, input.Tags)
+	record, _, _, err := r.usecase.CreateTransferRecord(ctx, userID, input.Title, input.Description, input.At, domain.AssetID(input.FromAssetID), domain.AssetID(input.ToAssetID), input.Amount, input.Tags, input.IdempotencyKey)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -79,7 +99,17 @@ func (r *mutationResolver) UpdateIncomeRecord(ctx context.Context, input domain.
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, err := r.usecase.UpdateIncomeRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags)
+	var categoryID *domain.RecordCategoryID
+	if input.CategoryID != nil {
+		categoryID = typeutil.Ptr(domain.RecordCategoryID(*input.CategoryID))
+	}
+
+	var payeeID *domain.PayeeID
+	if input.PayeeID != nil {
+		payeeID = typeutil.Ptr(domain.PayeeID(*input.PayeeID))
+	}
+
+	record, err := r.usecase.UpdateIncomeRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags, categoryID, input.ClearCategory, payeeID, input.ClearPayee, input.TaxRate, input.ClearTaxRate, input.ExpectedVersion)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -94,7 +124,17 @@ func (r *mutationResolver) UpdateExpenseRecord(ctx context.Context, input domain
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, err := r.usecase.UpdateExpenseRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags)
+	var categoryID *domain.RecordCategoryID
+	if input.CategoryID != nil {
+		categoryID = typeutil.Ptr(domain.RecordCategoryID(*input.CategoryID))
+	}
+
+	var payeeID *domain.PayeeID
+	if input.PayeeID != nil {
+		payeeID = typeutil.Ptr(domain.PayeeID(*input.PayeeID))
+	}
+
+	record, err := r.usecase.UpdateExpenseRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.AssetID), input.Amount, input.Tags, categoryID, input.ClearCategory, payeeID, input.ClearPayee, input.TaxRate, input.ClearTaxRate, input.ExpectedVersion)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -109,7 +149,53 @@ func (r *mutationResolver) UpdateTransferRecord(ctx context.Context, input domai
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	record, err := r.usecase.UpdateTransferRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.FromAssetID), domain.AssetID(input.ToAssetID), input.Amount, input.Tags)
+	record, err := r.usecase.UpdateTransferRecord(ctx, userID, domain.RecordID(input.ID), input.Title, input.Description, input.At, domain.AssetID(input.FromAssetID), domain.AssetID(input.ToAssetID), input.Amount, input.Tags, input.ExpectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return record, nil
+}
+
+// PatchRecord is the resolver for the patchRecord field.
+func (r *mutationResolver) PatchRecord(ctx context.Context, id string, input domain.PatchRecordInput) (*domain.Record, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	patch := &domain.RecordPatch{
+		Title:              input.Title,
+		Description:        input.Description,
+		At:                 input.At,
+		RecordType:         input.RecordType,
+		Amount:             input.Amount,
+		TagNames:           input.Tags,
+		ClearCategory:      input.ClearCategory,
+		ClearPayee:         input.ClearPayee,
+		TaxRate:            input.TaxRate,
+		ClearTaxRate:       input.ClearTaxRate,
+		TaxMode:            input.TaxMode,
+		BusinessRatio:      input.BusinessRatio,
+		ClearBusinessRatio: input.ClearBusinessRatio,
+	}
+	if input.AssetID != nil {
+		patch.AssetID = typeutil.Ptr(domain.AssetID(*input.AssetID))
+	}
+	if input.FromAssetID != nil {
+		patch.FromAssetID = typeutil.Ptr(domain.AssetID(*input.FromAssetID))
+	}
+	if input.ToAssetID != nil {
+		patch.ToAssetID = typeutil.Ptr(domain.AssetID(*input.ToAssetID))
+	}
+	if input.CategoryID != nil {
+		patch.CategoryID = typeutil.Ptr(domain.RecordCategoryID(*input.CategoryID))
+	}
+	if input.PayeeID != nil {
+		patch.PayeeID = typeutil.Ptr(domain.PayeeID(*input.PayeeID))
+	}
+
+	record, err := r.usecase.PatchRecord(ctx, userID, domain.RecordID(id), patch, input.ExpectedVersion)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -149,7 +235,7 @@ func (r *queryResolver) Record(ctx context.Context, id string) (*domain.Record,
 }
 
 // Records is the resolver for the records field.
-func (r *queryResolver) Records(ctx context.Context, assetID *string, sortKey domain.RecordSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.RecordConnection, error) {
+func (r *queryResolver) Records(ctx context.Context, filter *domain.RecordFilter, assetID *string, sortKey domain.RecordSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.RecordConnection, error) {
 	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortKey))
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -160,35 +246,44 @@ func (r *queryResolver) Records(ctx context.Context, assetID *string, sortKey do
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	var assetIDPtr *domain.AssetID
+	cond := domain.NewRecordConditionFromFilter(filter)
 	if assetID != nil {
-		assetIDPtr = typeutil.Ptr(domain.AssetID(*assetID))
+		cond.AssetIDs = append(cond.AssetIDs, domain.AssetID(*assetID))
 	}
 
-	records, pageInfo, err := r.usecase.GetRecordsByUserIDAndAssetID(ctx, pageParam, userID, assetIDPtr)
+	records, pageInfo, err := r.usecase.GetRecordsByCondition(ctx, pageParam, userID, cond)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	oldestRecord, err := records.OldestRecord(pageParam.IsReverse())
-	if err != nil {
-		return nil, xerrors.Errorf(": %w", err)
-	}
-
-	initTotalAssetAmount, err := r.usecase.CulcTotalAssetAmountAndCreateSnapshot(ctx, userID, assetIDPtr, oldestRecord.At, oldestRecord.ID)
-	if err != nil {
-		return nil, xerrors.Errorf(": %w", err)
+	// 資産合計は日時順に並べた場合のみ意味を持つため、それ以外のソートキーではnullを返す
+	var initTotalAssetAmount *int
+	if sortKey == domain.RecordSortKeyAt {
+		amount := 0
+		if len(records) > 0 {
+			oldestRecord, err := records.OldestRecord(pageParam.IsReverse())
+			if err != nil {
+				return nil, xerrors.Errorf(": %w", err)
+			}
+
+			amount, err = r.usecase.CulcTotalAssetAmountByAssetIDs(ctx, userID, cond.AssetIDs, oldestRecord.At, oldestRecord.ID)
+			if err != nil {
+				return nil, xerrors.Errorf(": %w", err)
+			}
+		}
+		initTotalAssetAmount = &amount
 	}
 
 	return &domain.RecordConnection{
 		Nodes:       records,
 		PageInfo:    pageInfo,
 		TotalAssets: initTotalAssetAmount,
+		Condition:   cond,
 	}, nil
 }
 
 // RecordsPerMonth is the resolver for the recordsPerMonth field.
-func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int, tagNames []string, assetIds []string, recordTypes []domain.RecordType, sortkey domain.RecordSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.RecordConnection, error) {
+func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int, filter *domain.RecordFilter, tagNames []string, assetIds []string, recordTypes []domain.RecordType, sortkey domain.RecordSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.RecordConnection, error) {
 	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortkey))
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -199,12 +294,14 @@ func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	argAssetIDs := make([]domain.AssetID, 0, len(assetIds))
+	cond := domain.NewRecordConditionFromFilter(filter).InMonth(year, month)
+	cond.AddTagCondition(tagNames, domain.TagMatchModeAny)
 	for _, assetID := range assetIds {
-		argAssetIDs = append(argAssetIDs, domain.AssetID(assetID))
+		cond.AssetIDs = append(cond.AssetIDs, domain.AssetID(assetID))
 	}
+	cond.RecordTypes = append(cond.RecordTypes, recordTypes...)
 
-	records, pageInfo, err := r.usecase.GetRecordsPerMonth(ctx, pageParam, userID, year, month, tagNames, argAssetIDs, recordTypes)
+	records, pageInfo, err := r.usecase.GetRecordsByCondition(ctx, pageParam, userID, cond)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -212,7 +309,8 @@ func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int
 	var cultTotalAssetBefore time.Time
 	var culcTotalAssetRecordID domain.RecordID
 
-	if len(records) > 0 {
+	// 日時順以外で並べた場合は先頭のRecordが最も古いとは限らないため、月初時点の資産合計を返す
+	if sortkey == domain.RecordSortKeyAt && len(records) > 0 {
 		oldestRecord, err := records.OldestRecord(pageParam.IsReverse())
 		if err != nil {
 			return nil, xerrors.Errorf(": %w", err)
@@ -220,11 +318,11 @@ func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int
 		cultTotalAssetBefore = oldestRecord.At
 		culcTotalAssetRecordID = oldestRecord.ID
 	} else {
-		cultTotalAssetBefore = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+		cultTotalAssetBefore = *cond.AtFrom
 		culcTotalAssetRecordID = domain.RecordID("")
 	}
 
-	initTotalAssetAmount, err := r.usecase.CulcTotalAssetAmountAndCreateSnapshot(ctx, userID, nil, cultTotalAssetBefore, culcTotalAssetRecordID)
+	initTotalAssetAmount, err := r.usecase.CulcTotalAssetAmountByAssetIDs(ctx, userID, cond.AssetIDs, cultTotalAssetBefore, culcTotalAssetRecordID)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -232,7 +330,8 @@ func (r *queryResolver) RecordsPerMonth(ctx context.Context, year int, month int
 	return &domain.RecordConnection{
 		Nodes:       records,
 		PageInfo:    pageInfo,
-		TotalAssets: initTotalAssetAmount,
+		TotalAssets: &initTotalAssetAmount,
+		Condition:   cond,
 	}, nil
 }
 
@@ -296,11 +395,92 @@ func (r *recordResolver) Tags(ctx context.Context, obj *domain.Record) ([]*domai
 	return tags, nil
 }
 
+// Category is the resolver for the category field.
+func (r *recordResolver) Category(ctx context.Context, obj *domain.Record) (*domain.RecordCategory, error) {
+	if obj.CategoryID == nil {
+		return nil, nil
+	}
+
+	thunk := r.Loaders.RecordCategoryLoader.Load(ctx, *obj.CategoryID)
+
+	category, err := thunk()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+// Payee is the resolver for the payee field.
+func (r *recordResolver) Payee(ctx context.Context, obj *domain.Record) (*domain.Payee, error) {
+	if obj.PayeeID == nil {
+		return nil, nil
+	}
+
+	payee, err := r.Loaders.PayeeLoader.Load(ctx, *obj.PayeeID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *recordConnectionResolver) TotalCount(ctx context.Context, obj *domain.RecordConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	count, err := r.usecase.CountRecordsByCondition(ctx, userID, obj.Condition)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
+// SumIncome is the resolver for the sumIncome field.
+func (r *recordConnectionResolver) SumIncome(ctx context.Context, obj *domain.RecordConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	sumIncome, err := r.usecase.SumIncomeByCondition(ctx, userID, obj.Condition)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return sumIncome, nil
+}
+
+// SumExpense is the resolver for the sumExpense field.
+func (r *recordConnectionResolver) SumExpense(ctx context.Context, obj *domain.RecordConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	sumExpense, err := r.usecase.SumExpenseByCondition(ctx, userID, obj.Condition)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return sumExpense, nil
+}
+
 // AssetChange returns graph.AssetChangeResolver implementation.
 func (r *Resolver) AssetChange() graph.AssetChangeResolver { return &assetChangeResolver{r} }
 
 // Record returns graph.RecordResolver implementation.
 func (r *Resolver) Record() graph.RecordResolver { return &recordResolver{r} }
 
+// RecordConnection returns graph.RecordConnectionResolver implementation.
+func (r *Resolver) RecordConnection() graph.RecordConnectionResolver {
+	return &recordConnectionResolver{r}
+}
+
 type assetChangeResolver struct{ *Resolver }
 type recordResolver struct{ *Resolver }
+type recordConnectionResolver struct{ *Resolver }
@@ -0,0 +1,88 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/lib/typeutil"
+
+	"golang.org/x/xerrors"
+)
+
+// BulkUpdateRecords is the resolver for the bulkUpdateRecords field.
+func (r *mutationResolver) BulkUpdateRecords(ctx context.Context, ids []string, patch domain.RecordBulkPatchInput) (*domain.BulkRecordsPayload, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	recordIDs := make([]domain.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, domain.RecordID(id))
+	}
+
+	recordPatch := &domain.RecordBulkPatch{
+		AddTagNames:    patch.AddTags,
+		RemoveTagNames: patch.RemoveTags,
+		Title:          patch.Title,
+	}
+	if patch.AssetID != nil {
+		recordPatch.AssetID = typeutil.Ptr(domain.AssetID(*patch.AssetID))
+	}
+	if patch.ShiftDays != nil {
+		recordPatch.ShiftDays = *patch.ShiftDays
+	}
+
+	payload, err := r.usecase.BulkUpdateRecords(ctx, userID, recordIDs, recordPatch)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payload, nil
+}
+
+// BulkDeleteRecords is the resolver for the bulkDeleteRecords field.
+func (r *mutationResolver) BulkDeleteRecords(ctx context.Context, ids []string) (*domain.BulkRecordsPayload, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	recordIDs := make([]domain.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, domain.RecordID(id))
+	}
+
+	payload, err := r.usecase.BulkDeleteRecords(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payload, nil
+}
+
+// ID is the resolver for the id field.
+func (r *recordBulkResultResolver) ID(ctx context.Context, obj *domain.RecordBulkResult) (string, error) {
+	return string(obj.ID), nil
+}
+
+// Error is the resolver for the error field.
+func (r *recordBulkResultResolver) Error(ctx context.Context, obj *domain.RecordBulkResult) (*string, error) {
+	if obj.Err == nil {
+		return nil, nil
+	}
+
+	return typeutil.Ptr(obj.Err.Error()), nil
+}
+
+// RecordBulkResult returns graph.RecordBulkResultResolver implementation.
+func (r *Resolver) RecordBulkResult() graph.RecordBulkResultResolver {
+	return &recordBulkResultResolver{r}
+}
+
+type recordBulkResultResolver struct{ *Resolver }
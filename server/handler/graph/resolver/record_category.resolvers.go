@@ -0,0 +1,181 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/lib/typeutil"
+
+	"golang.org/x/xerrors"
+)
+
+// CreateRecordCategory is the resolver for the createRecordCategory field.
+func (r *mutationResolver) CreateRecordCategory(ctx context.Context, input domain.CreateRecordCategoryInput) (*domain.RecordCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	var parentID *domain.RecordCategoryID
+	if input.ParentID != nil {
+		parentID = typeutil.Ptr(domain.RecordCategoryID(*input.ParentID))
+	}
+
+	category, err := r.usecase.CreateRecordCategory(ctx, userID, input.RecordType, input.Name, parentID, input.IdempotencyKey)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+// UpdateRecordCategory is the resolver for the updateRecordCategory field.
+func (r *mutationResolver) UpdateRecordCategory(ctx context.Context, input domain.UpdateRecordCategoryInput) (*domain.RecordCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	var parentID *domain.RecordCategoryID
+	if input.ParentID != nil {
+		parentID = typeutil.Ptr(domain.RecordCategoryID(*input.ParentID))
+	}
+
+	category, err := r.usecase.UpdateRecordCategory(ctx, userID, domain.RecordCategoryID(input.ID), input.Name, parentID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+// DeleteRecordCategory is the resolver for the deleteRecordCategory field.
+func (r *mutationResolver) DeleteRecordCategory(ctx context.Context, input domain.DeleteRecordCategoryInput) (*domain.RecordCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	categoryID, err := r.usecase.DeleteRecordCategory(ctx, userID, domain.RecordCategoryID(input.ID))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.RecordCategory{
+		ID: categoryID,
+	}, nil
+}
+
+// ConvertTagsToRecordCategories is the resolver for the convertTagsToRecordCategories field.
+func (r *mutationResolver) ConvertTagsToRecordCategories(ctx context.Context, input domain.ConvertTagsToRecordCategoriesInput) (*domain.ConvertTagsToRecordCategoriesPayload, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	tagIDs := make([]domain.TagID, 0, len(input.TagIDs))
+	for _, tagID := range input.TagIDs {
+		tagIDs = append(tagIDs, domain.TagID(tagID))
+	}
+
+	var parentID *domain.RecordCategoryID
+	if input.ParentID != nil {
+		parentID = typeutil.Ptr(domain.RecordCategoryID(*input.ParentID))
+	}
+
+	categories, assignedCount, err := r.usecase.ConvertTagsToRecordCategories(ctx, userID, tagIDs, input.RecordType, parentID, input.DeleteTags)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.ConvertTagsToRecordCategoriesPayload{
+		Categories:          categories,
+		AssignedRecordCount: assignedCount,
+	}, nil
+}
+
+// RecordCategories is the resolver for the recordCategories field.
+func (r *queryResolver) RecordCategories(ctx context.Context, recordType *domain.RecordType, rootOnly bool) ([]*domain.RecordCategory, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	categories, err := r.usecase.GetRecordCategoriesByRecordType(ctx, userID, recordType)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	if !rootOnly {
+		return categories, nil
+	}
+
+	rootCategories := make([]*domain.RecordCategory, 0, len(categories))
+	for _, category := range categories {
+		if category.ParentID == nil {
+			rootCategories = append(rootCategories, category)
+		}
+	}
+
+	return rootCategories, nil
+}
+
+// RecordCategorySummaries is the resolver for the recordCategorySummaries field.
+func (r *queryResolver) RecordCategorySummaries(ctx context.Context, recordType domain.RecordType, filter *domain.RecordFilter, maxDepth *int) ([]*domain.RecordCategorySummary, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	cond := domain.NewRecordConditionFromFilter(filter)
+
+	summaries, err := r.usecase.GetRecordCategorySummaries(ctx, userID, recordType, cond, maxDepth)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ID is the resolver for the id field.
+func (r *recordCategoryResolver) ID(ctx context.Context, obj *domain.RecordCategory) (string, error) {
+	return string(obj.ID), nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *recordCategoryResolver) Parent(ctx context.Context, obj *domain.RecordCategory) (*domain.RecordCategory, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	thunk := r.Loaders.RecordCategoryLoader.Load(ctx, *obj.ParentID)
+
+	parent, err := thunk()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return parent, nil
+}
+
+// Children is the resolver for the children field.
+func (r *recordCategoryResolver) Children(ctx context.Context, obj *domain.RecordCategory) ([]*domain.RecordCategory, error) {
+	thunk := r.Loaders.RecordCategoryChildrenLoader.Load(ctx, obj.ID)
+
+	children, err := thunk()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return children, nil
+}
+
+// RecordCategory returns graph.RecordCategoryResolver implementation.
+func (r *Resolver) RecordCategory() graph.RecordCategoryResolver { return &recordCategoryResolver{r} }
+
+type recordCategoryResolver struct{ *Resolver }
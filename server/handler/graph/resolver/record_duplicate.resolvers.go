@@ -0,0 +1,49 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// MergeRecords is the resolver for the mergeRecords field.
+func (r *mutationResolver) MergeRecords(ctx context.Context, keepID string, dropIDs []string) (*domain.Record, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	recordIDs := make([]domain.RecordID, 0, len(dropIDs))
+	for _, id := range dropIDs {
+		recordIDs = append(recordIDs, domain.RecordID(id))
+	}
+
+	record, err := r.usecase.MergeRecords(ctx, userID, domain.RecordID(keepID), recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return record, nil
+}
+
+// DuplicateCandidates is the resolver for the duplicateCandidates field.
+func (r *queryResolver) DuplicateCandidates(ctx context.Context, from time.Time, to time.Time) ([]*domain.DuplicateCandidate, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	candidates, err := r.usecase.GetDuplicateCandidates(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return candidates, nil
+}
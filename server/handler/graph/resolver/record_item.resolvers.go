@@ -0,0 +1,77 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"golang.org/x/xerrors"
+)
+
+// SetRecordItems is the resolver for the setRecordItems field.
+func (r *mutationResolver) SetRecordItems(ctx context.Context, input domain.SetRecordItemsInput) (*domain.Record, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	params := make([]*domain.RecordItemParam, 0, len(input.Items))
+	for _, item := range input.Items {
+		params = append(params, &domain.RecordItemParam{
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			TaxRate:   item.TaxRate,
+			TagNames:  item.Tags,
+		})
+	}
+
+	record, err := r.usecase.SetRecordItems(ctx, userID, domain.RecordID(input.RecordID), params, input.TaxMode, input.ExpectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return record, nil
+}
+
+// TagSummaries is the resolver for the tagSummaries field.
+func (r *queryResolver) TagSummaries(ctx context.Context, recordType domain.RecordType, filter *domain.RecordFilter) ([]*domain.TagSummary, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	cond := domain.NewRecordConditionFromFilter(filter)
+
+	summaries, err := r.usecase.GetTagSummaries(ctx, userID, recordType, cond)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return summaries, nil
+}
+
+// Items is the resolver for the items field.
+func (r *recordResolver) Items(ctx context.Context, obj *domain.Record) ([]*domain.RecordItem, error) {
+	items, err := r.Loaders.RecordItemLoader.Load(ctx, obj.ID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return items, nil
+}
+
+// ID is the resolver for the id field.
+func (r *recordItemResolver) ID(ctx context.Context, obj *domain.RecordItem) (string, error) {
+	return string(obj.ID), nil
+}
+
+// RecordItem returns graph.RecordItemResolver implementation.
+func (r *Resolver) RecordItem() graph.RecordItemResolver { return &recordItemResolver{r} }
+
+type recordItemResolver struct{ *Resolver }
@@ -0,0 +1,114 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/lib/ctxdef"
+
+	"golang.org/x/xerrors"
+)
+
+// SearchRecords is the resolver for the searchRecords field.
+func (r *queryResolver) SearchRecords(ctx context.Context, query string, filter *domain.RecordFilter, first int, after *domain.PageCursor) (*domain.RecordSearchConnection, error) {
+	pageParam, err := domain.NewPageParam(&first, after, nil, nil, domain.SearchSortKeyScore)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	cond := domain.NewRecordConditionFromFilter(filter)
+
+	recordWithScores, pageInfo, err := r.usecase.SearchRecords(ctx, pageParam, userID, query, cond)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	results := make([]*domain.RecordSearchResult, 0, len(recordWithScores))
+	for _, recordWithScore := range recordWithScores {
+		results = append(results, &domain.RecordSearchResult{
+			Record: &recordWithScore.Record,
+			Score:  recordWithScore.Score(),
+			Query:  query,
+		})
+	}
+
+	return &domain.RecordSearchConnection{
+		Nodes:     results,
+		PageInfo:  pageInfo,
+		Query:     query,
+		Condition: cond,
+	}, nil
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *recordSearchConnectionResolver) TotalCount(ctx context.Context, obj *domain.RecordSearchConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	count, err := r.usecase.CountSearchRecords(ctx, userID, obj.Query, obj.Condition)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
+// Highlights is the resolver for the highlights field.
+func (r *recordSearchResultResolver) Highlights(ctx context.Context, obj *domain.RecordSearchResult) ([]*domain.SearchHighlight, error) {
+	record := obj.Record
+
+	targets := []domain.SearchHighlightTarget{
+		{Field: domain.SearchHighlightFieldTitle, Text: record.Title},
+		{Field: domain.SearchHighlightFieldDescription, Text: record.Description},
+	}
+
+	tags, err := r.Loaders.TagLoader.Load(ctx, record.ID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, tag := range tags {
+		targets = append(targets, domain.SearchHighlightTarget{Field: domain.SearchHighlightFieldTag, Text: tag.Name})
+	}
+
+	assetChangesAssociation, err := r.AssetChangeLoader.Load(ctx, record.ID)()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, assetChange := range []*domain.AssetChange{assetChangesAssociation.AssetChangeIncome, assetChangesAssociation.AssetChangeExpense} {
+		if assetChange == nil {
+			continue
+		}
+
+		asset, err := r.Loaders.AssetLoader.Load(ctx, assetChange.AssetID)()
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+		targets = append(targets, domain.SearchHighlightTarget{Field: domain.SearchHighlightFieldAsset, Text: asset.Name})
+	}
+
+	return domain.NewSearchHighlights(targets, obj.Query), nil
+}
+
+// RecordSearchConnection returns graph.RecordSearchConnectionResolver implementation.
+func (r *Resolver) RecordSearchConnection() graph.RecordSearchConnectionResolver {
+	return &recordSearchConnectionResolver{r}
+}
+
+// RecordSearchResult returns graph.RecordSearchResultResolver implementation.
+func (r *Resolver) RecordSearchResult() graph.RecordSearchResultResolver {
+	return &recordSearchResultResolver{r}
+}
+
+type recordSearchConnectionResolver struct{ *Resolver }
+type recordSearchResultResolver struct{ *Resolver }
@@ -9,6 +9,8 @@ import (
 	"kakeibo-web-server/domain"
 	"kakeibo-web-server/handler/graph"
 	"kakeibo-web-server/lib/ctxdef"
+	"kakeibo-web-server/lib/typeutil"
+	"time"
 
 	"golang.org/x/xerrors"
 )
@@ -20,7 +22,7 @@ func (r *mutationResolver) CreateTag(ctx context.Context, input domain.CreateTag
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	tag, err := r.usecase.CreateTag(ctx, userID, input.Name)
+	tag, err := r.usecase.CreateTag(ctx, userID, input.Name, input.Color, input.Icon, input.IdempotencyKey)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -35,7 +37,7 @@ func (r *mutationResolver) UpdateTag(ctx context.Context, input domain.UpdateTag
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	tag, err := r.usecase.UpdateTag(ctx, userID, domain.TagID(input.ID), input.Name)
+	tag, err := r.usecase.UpdateTag(ctx, userID, domain.TagID(input.ID), input.Name, input.Color, input.Icon, input.Archived, input.ExpectedVersion)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -50,7 +52,12 @@ func (r *mutationResolver) DeleteTag(ctx context.Context, input domain.DeleteTag
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	tag, err := r.usecase.DeleteTag(ctx, userID, domain.TagID(input.ID))
+	var reassignTo *domain.TagID
+	if input.ReassignTo != nil {
+		reassignTo = typeutil.Ptr(domain.TagID(*input.ReassignTo))
+	}
+
+	tag, err := r.usecase.DeleteTag(ctx, userID, domain.TagID(input.ID), reassignTo)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -60,8 +67,48 @@ func (r *mutationResolver) DeleteTag(ctx context.Context, input domain.DeleteTag
 	}, nil
 }
 
+// MergeTags is the resolver for the mergeTags field.
+func (r *mutationResolver) MergeTags(ctx context.Context, input domain.MergeTagsInput) (*domain.Tag, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	sourceIDs := make([]domain.TagID, 0, len(input.SourceIDs))
+	for _, sourceID := range input.SourceIDs {
+		sourceIDs = append(sourceIDs, domain.TagID(sourceID))
+	}
+
+	tag, err := r.usecase.MergeTags(ctx, userID, sourceIDs, domain.TagID(input.TargetID))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tag, nil
+}
+
+// ReorderTags is the resolver for the reorderTags field.
+func (r *mutationResolver) ReorderTags(ctx context.Context, ids []string) ([]*domain.Tag, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	tagIDs := make([]domain.TagID, 0, len(ids))
+	for _, id := range ids {
+		tagIDs = append(tagIDs, domain.TagID(id))
+	}
+
+	tags, err := r.usecase.ReorderTags(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tags, nil
+}
+
 // Tags is the resolver for the tags field.
-func (r *queryResolver) Tags(ctx context.Context, sortKey domain.TagSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.TagConnection, error) {
+func (r *queryResolver) Tags(ctx context.Context, includeArchived bool, sortKey domain.TagSortKey, first *int, after *domain.PageCursor, last *int, before *domain.PageCursor) (*domain.TagConnection, error) {
 	pageParam, err := domain.NewPageParam(first, after, last, before, string(sortKey))
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -72,23 +119,92 @@ func (r *queryResolver) Tags(ctx context.Context, sortKey domain.TagSortKey, fir
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
-	tags, pageInfo, err := r.usecase.GetTagsByUserID(ctx, pageParam, userID)
+	tags, pageInfo, err := r.usecase.GetTagsByUserID(ctx, pageParam, userID, includeArchived)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
 
 	return &domain.TagConnection{
-		Nodes:    tags,
-		PageInfo: pageInfo,
+		Nodes:           tags,
+		PageInfo:        pageInfo,
+		IncludeArchived: includeArchived,
 	}, nil
 }
 
+// SuggestTags is the resolver for the suggestTags field.
+func (r *queryResolver) SuggestTags(ctx context.Context, prefix string, title *string, assetID *string, limit int) ([]*domain.Tag, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	var recordTitle string
+	if title != nil {
+		recordTitle = *title
+	}
+
+	var assetIDPtr *domain.AssetID
+	if assetID != nil {
+		assetIDPtr = typeutil.Ptr(domain.AssetID(*assetID))
+	}
+
+	tags, err := r.usecase.SuggestTags(ctx, userID, prefix, recordTitle, assetIDPtr, limit)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tags, nil
+}
+
 // ID is the resolver for the id field.
 func (r *tagResolver) ID(ctx context.Context, obj *domain.Tag) (string, error) {
 	return string(obj.ID), nil
 }
 
+// RecordCount is the resolver for the recordCount field.
+func (r *tagResolver) RecordCount(ctx context.Context, obj *domain.Tag) (int, error) {
+	thunk := r.Loaders.TagStatLoader.Load(ctx, obj.ID)
+
+	stat, err := thunk()
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return stat.RecordCount, nil
+}
+
+// LastUsedAt is the resolver for the lastUsedAt field.
+func (r *tagResolver) LastUsedAt(ctx context.Context, obj *domain.Tag) (*time.Time, error) {
+	thunk := r.Loaders.TagStatLoader.Load(ctx, obj.ID)
+
+	stat, err := thunk()
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return stat.LastUsedAt, nil
+}
+
+// TotalCount is the resolver for the totalCount field.
+func (r *tagConnectionResolver) TotalCount(ctx context.Context, obj *domain.TagConnection) (int, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	count, err := r.usecase.CountTagsByUserID(ctx, userID, obj.IncludeArchived)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
 // Tag returns graph.TagResolver implementation.
 func (r *Resolver) Tag() graph.TagResolver { return &tagResolver{r} }
 
+// TagConnection returns graph.TagConnectionResolver implementation.
+func (r *Resolver) TagConnection() graph.TagConnectionResolver { return &tagConnectionResolver{r} }
+
 type tagResolver struct{ *Resolver }
+type tagConnectionResolver struct{ *Resolver }
@@ -0,0 +1,44 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.72
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"kakeibo-web-server/lib/ctxdef"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// UpdateTaxRounding is the resolver for the updateTaxRounding field.
+func (r *mutationResolver) UpdateTaxRounding(ctx context.Context, rounding domain.TaxRounding) (*domain.User, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	user, err := r.usecase.UpdateTaxRounding(ctx, userID, rounding)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return user, nil
+}
+
+// TaxSummary is the resolver for the taxSummary field.
+func (r *queryResolver) TaxSummary(ctx context.Context, from time.Time, to time.Time) (*domain.TaxSummary, error) {
+	userID, err := ctxdef.UserID(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	summary, err := r.usecase.GetTaxSummary(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,115 @@
+package kana
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize は文字列の比較用に、全角・半角をNFKCで統一し、英字を小文字に、カタカナをひらがなに変換する
+func Normalize(s string) string {
+	s = strings.ToLower(norm.NFKC.String(s))
+
+	return strings.Map(func(r rune) rune {
+		// カタカナ（ァ〜ヶ）はひらがなと同じ並びで0x60だけずれている
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
+
+// romajiTable はローマ字（ヘボン式・訓令式）からひらがなへの対応。長い綴りから順に照合する
+var romajiTable = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"sa": "さ", "si": "し", "shi": "し", "su": "す", "se": "せ", "so": "そ",
+	"ta": "た", "ti": "ち", "chi": "ち", "tu": "つ", "tsu": "つ", "te": "て", "to": "と",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"ha": "は", "hi": "ひ", "hu": "ふ", "fu": "ふ", "he": "へ", "ho": "ほ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"wa": "わ", "wo": "を", "nn": "ん",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"za": "ざ", "zi": "じ", "ji": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sya": "しゃ", "syu": "しゅ", "syo": "しょ", "sha": "しゃ", "shu": "しゅ", "sho": "しょ", "she": "しぇ",
+	"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ", "cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ", "che": "ちぇ",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ", "ja": "じゃ", "ju": "じゅ", "jo": "じょ", "je": "じぇ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"-": "ー",
+}
+
+const romajiMaxLength = 3
+
+// RomajiToHiragana はローマ字をひらがなに変換する。変換できない文字はそのまま残す
+// 入力途中の綴り（"shok" の "k" など）は変換されずに末尾に残る
+func RomajiToHiragana(s string) string {
+	s = strings.ToLower(s)
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		// 子音の重なりは促音にする（"kk" → "っk"）。nは撥音の扱いになるため除く
+		if i+1 < len(s) && s[i] == s[i+1] && isConsonant(s[i]) && s[i] != 'n' {
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		// 母音・y以外の前、または末尾でないnは撥音にする（"kanji" → "かんじ"）
+		if s[i] == 'n' && i+1 < len(s) && !isVowel(s[i+1]) && s[i+1] != 'y' && s[i+1] != 'n' {
+			b.WriteString("ん")
+			i++
+			continue
+		}
+
+		matched := false
+		for length := romajiMaxLength; length > 0; length-- {
+			if i+length > len(s) {
+				continue
+			}
+			if kana, ok := romajiTable[s[i:i+length]]; ok {
+				b.WriteString(kana)
+				i += length
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		r := []rune(s[i:])[0]
+		b.WriteRune(r)
+		i += len(string(r))
+	}
+
+	return b.String()
+}
+
+// TrimIncompleteRomaji は末尾に残った変換途中のローマ字を取り除く
+func TrimIncompleteRomaji(s string) string {
+	return strings.TrimRightFunc(s, func(r rune) bool {
+		return r < unicode.MaxASCII && unicode.IsLetter(r)
+	})
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aiueo", c) >= 0
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c)
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// LocalStorage はローカルのファイルシステムに保存する
+// 署名付きURLはこのサーバー自身のURLになるため、ServeHTTPをURLのパスにマウントする必要がある
+type LocalStorage struct {
+	dir     string
+	baseURL string // ServeHTTPをマウントしたURL（例: http://localhost:8080/files）
+	secret  []byte
+}
+
+func NewLocalStorage(dir string, baseURL string, secret []byte) (*LocalStorage, error) {
+	err := os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return xerrors.Errorf("failed to create directory: %w", err)
+	}
+
+	// 書き込み途中のファイルを読まれないように、一時ファイルに書き込んでから置き換える
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return xerrors.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if err != nil {
+		tmp.Close()
+		return xerrors.Errorf("failed to write file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return xerrors.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, xerrors.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return xerrors.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	_, err := s.path(key)
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiresIn).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+
+	return s.baseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// ServeHTTP は署名付きURLのファイルを返す。署名が一致しない場合や期限切れの場合は403を返す
+// StripPrefixでbaseURLのパスを取り除いてからマウントする
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	expires := r.URL.Query().Get("expires")
+	signature := r.URL.Query().Get("signature")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt || !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "failed to read file", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=0")
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), file)
+}
+
+func (s *LocalStorage) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path はkeyに対応するファイルのパスを返す。保存先の外を指すkeyはエラーにする
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", xerrors.Errorf("invalid storage key %q", key)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+var ErrObjectNotFound = xerrors.New("object not found")
+
+// Storage は添付ファイルなどのバイナリを保存する。ローカルのファイルシステムのほか、S3互換のストレージに差し替えられる
+// keyは "/" 区切りのパスで、実装ごとの保存先からの相対位置を表す
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete は保存したオブジェクトを削除する。存在しない場合はエラーにしない
+	Delete(ctx context.Context, key string) error
+	// SignedURL は認証なしでダウンロードできる、expiresInの間だけ有効なURLを返す
+	SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
+}
@@ -0,0 +1,21 @@
+-- RecordのIDに誤って付与されていた種別 "_User" を "_Record" に置き換える
+-- record.id を参照している外部キーは ON UPDATE CASCADE を持たないため、参照元も同時に更新する
+START TRANSACTION;
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+UPDATE record_tag
+SET record_id = CONCAT(SUBSTRING_INDEX(record_id, '_', 1), '_Record')
+WHERE record_id LIKE '%\_User';
+
+UPDATE asset_change
+SET record_id = CONCAT(SUBSTRING_INDEX(record_id, '_', 1), '_Record')
+WHERE record_id LIKE '%\_User';
+
+UPDATE record
+SET id = CONCAT(SUBSTRING_INDEX(id, '_', 1), '_Record')
+WHERE id LIKE '%\_User';
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+COMMIT;
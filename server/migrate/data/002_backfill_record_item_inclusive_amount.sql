@@ -0,0 +1,41 @@
+-- 明細の税込金額（inclusive_amount）を求め直す
+-- 単価が税抜のRecordは、税率ごとの合計に対してRecordの端数処理で求めた消費税額を明細の金額の比で割り振り、割り切れない分は税率ごとの最後の明細に寄せる
+START TRANSACTION;
+
+UPDATE record_item ri
+JOIN (
+    SELECT
+        item.id,
+        item.amount + CASE
+            WHEN item.tax_mode <> 'EXCLUSIVE' THEN 0
+            WHEN item.reverse_order = 1 THEN item.group_tax - (SUM(item.allocated_tax) OVER w - item.allocated_tax)
+            ELSE item.allocated_tax
+        END AS inclusive_amount
+    FROM (
+        SELECT
+            g.*,
+            CASE WHEN g.group_amount = 0 THEN 0 ELSE (g.group_tax * g.amount) DIV g.group_amount END AS allocated_tax
+        FROM (
+            SELECT
+                ri.id,
+                ri.record_id,
+                ri.tax_rate,
+                rc.tax_mode,
+                ri.quantity * ri.unit_price AS amount,
+                SUM(ri.quantity * ri.unit_price) OVER p AS group_amount,
+                CASE rc.tax_rounding
+                    WHEN 'CEIL' THEN CEIL(SUM(ri.quantity * ri.unit_price) OVER p * IF(ri.tax_rate = 'REDUCED', 8, 10) / 100)
+                    WHEN 'ROUND' THEN ROUND(SUM(ri.quantity * ri.unit_price) OVER p * IF(ri.tax_rate = 'REDUCED', 8, 10) / 100)
+                    ELSE FLOOR(SUM(ri.quantity * ri.unit_price) OVER p * IF(ri.tax_rate = 'REDUCED', 8, 10) / 100)
+                END AS group_tax,
+                ROW_NUMBER() OVER (PARTITION BY ri.record_id, ri.tax_rate ORDER BY ri.sort_order DESC) AS reverse_order
+            FROM record_item ri
+            JOIN record rc ON rc.id = ri.record_id
+            WINDOW p AS (PARTITION BY ri.record_id, ri.tax_rate)
+        ) g
+    ) item
+    WINDOW w AS (PARTITION BY item.record_id, item.tax_rate)
+) t ON t.id = ri.id
+SET ri.inclusive_amount = t.inclusive_amount;
+
+COMMIT;
@@ -0,0 +1,125 @@
+-- Tag名をNFKCで正規化した名前に置き換える（Tag名の正規化を導入する前に作成したTagが、正規化した名前の絞り込みで一致しないため）
+-- MySQLにはNFKCがないため、Tag名に現れうる全角・半角の文字（全角英数記号、半角カナ、全角スペース）の対応表で置き換える
+-- 正規化すると同じ名前になるTagは最も古いTagにまとめ、RecordとRecordItemへの付与を付け替えてから残りを削除する
+-- DDLは暗黙にコミットするため、作業用のテーブルはトランザクションの外で作成・削除する
+CREATE TABLE tmp_tag_name_map (
+    n INT NOT NULL, -- 置き換える順。濁点・半濁点の付いた半角カナを先に置き換える
+    src VARCHAR(2) NOT NULL,
+    dst VARCHAR(2) NOT NULL,
+    PRIMARY KEY (n)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE tmp_tag_merge (
+    id VARCHAR(255) NOT NULL,
+    survivor_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL, -- tag.nameと同じ照合順序で比較し、UNIQUE (user_id, name) と同じ単位でまとめる
+    archived BOOLEAN NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_tmp_tag_merge_survivor (survivor_id)
+);
+
+INSERT INTO tmp_tag_name_map (n, src, dst) VALUES
+    (1, 'ｦﾞ', 'ヺ'), (2, 'ｳﾞ', 'ヴ'), (3, 'ｶﾞ', 'ガ'), (4, 'ｷﾞ', 'ギ'), (5, 'ｸﾞ', 'グ'), (6, 'ｹﾞ', 'ゲ'),
+    (7, 'ｺﾞ', 'ゴ'), (8, 'ｻﾞ', 'ザ'), (9, 'ｼﾞ', 'ジ'), (10, 'ｽﾞ', 'ズ'), (11, 'ｾﾞ', 'ゼ'), (12, 'ｿﾞ', 'ゾ'),
+    (13, 'ﾀﾞ', 'ダ'), (14, 'ﾁﾞ', 'ヂ'), (15, 'ﾂﾞ', 'ヅ'), (16, 'ﾃﾞ', 'デ'), (17, 'ﾄﾞ', 'ド'), (18, 'ﾊﾞ', 'バ'),
+    (19, 'ﾊﾟ', 'パ'), (20, 'ﾋﾞ', 'ビ'), (21, 'ﾋﾟ', 'ピ'), (22, 'ﾌﾞ', 'ブ'), (23, 'ﾌﾟ', 'プ'), (24, 'ﾍﾞ', 'ベ'),
+    (25, 'ﾍﾟ', 'ペ'), (26, 'ﾎﾞ', 'ボ'), (27, 'ﾎﾟ', 'ポ'), (28, 'ﾜﾞ', 'ヷ'), (29, '　', ' '), (30, '！', '!'),
+    (31, '＂', '"'), (32, '＃', '#'), (33, '＄', '$'), (34, '％', '%'), (35, '＆', '&'), (36, '＇', ''''),
+    (37, '（', '('), (38, '）', ')'), (39, '＊', '*'), (40, '＋', '+'), (41, '，', ','), (42, '－', '-'),
+    (43, '．', '.'), (44, '／', '/'), (45, '０', '0'), (46, '１', '1'), (47, '２', '2'), (48, '３', '3'),
+    (49, '４', '4'), (50, '５', '5'), (51, '６', '6'), (52, '７', '7'), (53, '８', '8'), (54, '９', '9'),
+    (55, '：', ':'), (56, '；', ';'), (57, '＜', '<'), (58, '＝', '='), (59, '＞', '>'), (60, '？', '?'),
+    (61, '＠', '@'), (62, 'Ａ', 'A'), (63, 'Ｂ', 'B'), (64, 'Ｃ', 'C'), (65, 'Ｄ', 'D'), (66, 'Ｅ', 'E'),
+    (67, 'Ｆ', 'F'), (68, 'Ｇ', 'G'), (69, 'Ｈ', 'H'), (70, 'Ｉ', 'I'), (71, 'Ｊ', 'J'), (72, 'Ｋ', 'K'),
+    (73, 'Ｌ', 'L'), (74, 'Ｍ', 'M'), (75, 'Ｎ', 'N'), (76, 'Ｏ', 'O'), (77, 'Ｐ', 'P'), (78, 'Ｑ', 'Q'),
+    (79, 'Ｒ', 'R'), (80, 'Ｓ', 'S'), (81, 'Ｔ', 'T'), (82, 'Ｕ', 'U'), (83, 'Ｖ', 'V'), (84, 'Ｗ', 'W'),
+    (85, 'Ｘ', 'X'), (86, 'Ｙ', 'Y'), (87, 'Ｚ', 'Z'), (88, '［', '['), (89, '＼', '\\'), (90, '］', ']'),
+    (91, '＾', '^'), (92, '＿', '_'), (93, '｀', '`'), (94, 'ａ', 'a'), (95, 'ｂ', 'b'), (96, 'ｃ', 'c'),
+    (97, 'ｄ', 'd'), (98, 'ｅ', 'e'), (99, 'ｆ', 'f'), (100, 'ｇ', 'g'), (101, 'ｈ', 'h'), (102, 'ｉ', 'i'),
+    (103, 'ｊ', 'j'), (104, 'ｋ', 'k'), (105, 'ｌ', 'l'), (106, 'ｍ', 'm'), (107, 'ｎ', 'n'), (108, 'ｏ', 'o'),
+    (109, 'ｐ', 'p'), (110, 'ｑ', 'q'), (111, 'ｒ', 'r'), (112, 'ｓ', 's'), (113, 'ｔ', 't'), (114, 'ｕ', 'u'),
+    (115, 'ｖ', 'v'), (116, 'ｗ', 'w'), (117, 'ｘ', 'x'), (118, 'ｙ', 'y'), (119, 'ｚ', 'z'), (120, '｛', '{'),
+    (121, '｜', '|'), (122, '｝', '}'), (123, '～', '~'), (124, '｟', '⦅'), (125, '｠', '⦆'), (126, '｡', '。'),
+    (127, '｢', '「'), (128, '｣', '」'), (129, '､', '、'), (130, '･', '・'), (131, 'ｦ', 'ヲ'), (132, 'ｧ', 'ァ'),
+    (133, 'ｨ', 'ィ'), (134, 'ｩ', 'ゥ'), (135, 'ｪ', 'ェ'), (136, 'ｫ', 'ォ'), (137, 'ｬ', 'ャ'), (138, 'ｭ', 'ュ'),
+    (139, 'ｮ', 'ョ'), (140, 'ｯ', 'ッ'), (141, 'ｰ', 'ー'), (142, 'ｱ', 'ア'), (143, 'ｲ', 'イ'), (144, 'ｳ', 'ウ'),
+    (145, 'ｴ', 'エ'), (146, 'ｵ', 'オ'), (147, 'ｶ', 'カ'), (148, 'ｷ', 'キ'), (149, 'ｸ', 'ク'), (150, 'ｹ', 'ケ'),
+    (151, 'ｺ', 'コ'), (152, 'ｻ', 'サ'), (153, 'ｼ', 'シ'), (154, 'ｽ', 'ス'), (155, 'ｾ', 'セ'), (156, 'ｿ', 'ソ'),
+    (157, 'ﾀ', 'タ'), (158, 'ﾁ', 'チ'), (159, 'ﾂ', 'ツ'), (160, 'ﾃ', 'テ'), (161, 'ﾄ', 'ト'), (162, 'ﾅ', 'ナ'),
+    (163, 'ﾆ', 'ニ'), (164, 'ﾇ', 'ヌ'), (165, 'ﾈ', 'ネ'), (166, 'ﾉ', 'ノ'), (167, 'ﾊ', 'ハ'), (168, 'ﾋ', 'ヒ'),
+    (169, 'ﾌ', 'フ'), (170, 'ﾍ', 'ヘ'), (171, 'ﾎ', 'ホ'), (172, 'ﾏ', 'マ'), (173, 'ﾐ', 'ミ'), (174, 'ﾑ', 'ム'),
+    (175, 'ﾒ', 'メ'), (176, 'ﾓ', 'モ'), (177, 'ﾔ', 'ヤ'), (178, 'ﾕ', 'ユ'), (179, 'ﾖ', 'ヨ'), (180, 'ﾗ', 'ラ'),
+    (181, 'ﾘ', 'リ'), (182, 'ﾙ', 'ル'), (183, 'ﾚ', 'レ'), (184, 'ﾛ', 'ロ'), (185, 'ﾜ', 'ワ'), (186, 'ﾝ', 'ン'),
+    (187, 'ﾞ', '゙'), (188, 'ﾟ', '゚'), (189, 'ﾠ', 'ᅠ'), (190, 'ﾡ', 'ᄀ'), (191, 'ﾢ', 'ᄁ'), (192, 'ﾣ', 'ᆪ'),
+    (193, 'ﾤ', 'ᄂ'), (194, 'ﾥ', 'ᆬ'), (195, 'ﾦ', 'ᆭ'), (196, 'ﾧ', 'ᄃ'), (197, 'ﾨ', 'ᄄ'), (198, 'ﾩ', 'ᄅ'),
+    (199, 'ﾪ', 'ᆰ'), (200, 'ﾫ', 'ᆱ'), (201, 'ﾬ', 'ᆲ'), (202, 'ﾭ', 'ᆳ'), (203, 'ﾮ', 'ᆴ'), (204, 'ﾯ', 'ᆵ'),
+    (205, 'ﾰ', 'ᄚ'), (206, 'ﾱ', 'ᄆ'), (207, 'ﾲ', 'ᄇ'), (208, 'ﾳ', 'ᄈ'), (209, 'ﾴ', 'ᄡ'), (210, 'ﾵ', 'ᄉ'),
+    (211, 'ﾶ', 'ᄊ'), (212, 'ﾷ', 'ᄋ'), (213, 'ﾸ', 'ᄌ'), (214, 'ﾹ', 'ᄍ'), (215, 'ﾺ', 'ᄎ'), (216, 'ﾻ', 'ᄏ'),
+    (217, 'ﾼ', 'ᄐ'), (218, 'ﾽ', 'ᄑ'), (219, 'ﾾ', 'ᄒ'), (220, 'ￂ', 'ᅡ'), (221, 'ￃ', 'ᅢ'), (222, 'ￄ', 'ᅣ'),
+    (223, 'ￅ', 'ᅤ'), (224, 'ￆ', 'ᅥ'), (225, 'ￇ', 'ᅦ'), (226, 'ￊ', 'ᅧ'), (227, 'ￋ', 'ᅨ'), (228, 'ￌ', 'ᅩ'),
+    (229, 'ￍ', 'ᅪ'), (230, 'ￎ', 'ᅫ'), (231, 'ￏ', 'ᅬ'), (232, 'ￒ', 'ᅭ'), (233, 'ￓ', 'ᅮ'), (234, 'ￔ', 'ᅯ'),
+    (235, 'ￕ', 'ᅰ'), (236, 'ￖ', 'ᅱ'), (237, 'ￗ', 'ᅲ'), (238, 'ￚ', 'ᅳ'), (239, 'ￛ', 'ᅴ'), (240, 'ￜ', 'ᅵ'),
+    (241, '￠', '¢'), (242, '￡', '£'), (243, '￢', '¬'), (244, '￣', ' ̄'), (245, '￤', '¦'), (246, '￥', '¥'),
+    (247, '￦', '₩'), (248, '￨', '│'), (249, '￩', '←'), (250, '￪', '↑'), (251, '￫', '→'), (252, '￬', '↓'),
+    (253, '￭', '■'), (254, '￮', '○');
+
+SET @tag_name_map_count = (SELECT COUNT(*) FROM tmp_tag_name_map);
+
+INSERT INTO tmp_tag_merge (id, survivor_id, name, archived)
+WITH RECURSIVE normalized (id, name, n) AS (
+    SELECT id, name, 0 FROM tag
+    UNION ALL
+    SELECT normalized.id, REPLACE(normalized.name, m.src, m.dst), normalized.n + 1
+    FROM normalized
+    JOIN tmp_tag_name_map m ON m.n = normalized.n + 1
+)
+SELECT
+    t.id,
+    FIRST_VALUE(t.id) OVER (PARTITION BY t.user_id, TRIM(normalized.name) ORDER BY t.created_at, t.id),
+    TRIM(normalized.name),
+    t.archived
+FROM normalized
+JOIN tag t ON t.id = normalized.id
+WHERE normalized.n = @tag_name_map_count
+    AND TRIM(normalized.name) <> '';
+
+START TRANSACTION;
+
+INSERT IGNORE INTO record_tag (record_id, tag_id)
+SELECT rt.record_id, m.survivor_id
+FROM record_tag rt
+JOIN tmp_tag_merge m ON m.id = rt.tag_id
+WHERE m.id <> m.survivor_id;
+
+INSERT IGNORE INTO record_item_tag (record_item_id, tag_id)
+SELECT rit.record_item_id, m.survivor_id
+FROM record_item_tag rit
+JOIN tmp_tag_merge m ON m.id = rit.tag_id
+WHERE m.id <> m.survivor_id;
+
+-- まとめたTagのいずれかがアーカイブされていなければ、まとめた先もアーカイブしない
+UPDATE tag t
+JOIN (
+    SELECT survivor_id, MIN(archived) AS archived
+    FROM tmp_tag_merge
+    GROUP BY survivor_id
+    HAVING COUNT(*) > 1
+) g ON g.survivor_id = t.id
+SET t.archived = g.archived,
+    t.version = t.version + 1;
+
+-- 付与はON DELETE CASCADEで削除される
+DELETE t FROM tag t
+JOIN tmp_tag_merge m ON m.id = t.id
+WHERE m.id <> m.survivor_id;
+
+UPDATE tag t
+JOIN tmp_tag_merge m ON m.id = t.id
+SET t.name = m.name,
+    t.version = t.version + 1
+WHERE BINARY t.name <> BINARY m.name;
+
+COMMIT;
+
+DROP TABLE tmp_tag_merge;
+DROP TABLE tmp_tag_name_map;
@@ -1,6 +1,7 @@
 CREATE TABLE IF NOT EXISTS user (
     id VARCHAR(255),
     name VARCHAR(255) NOT NULL,
+    tax_rounding VARCHAR(32) NOT NULL DEFAULT 'FLOOR', -- 消費税額の端数処理。FLOOR・ROUND・CEIL
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (id),
@@ -11,10 +12,12 @@ CREATE TABLE IF NOT EXISTS asset_category (
     id VARCHAR(255),
     user_id VARCHAR(255) NOT NULL,
     name VARCHAR(255) NOT NULL,
+    parent_id VARCHAR(255),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (id),
-    CONSTRAINT fk_asset_category_user FOREIGN KEY (user_id) REFERENCES user(id)
+    CONSTRAINT fk_asset_category_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_asset_category_parent FOREIGN KEY (parent_id) REFERENCES asset_category(id)
 );
 
 CREATE TABLE IF NOT EXISTS asset (
@@ -22,9 +25,14 @@ CREATE TABLE IF NOT EXISTS asset (
     user_id VARCHAR(255) NOT NULL,
     name VARCHAR(255) NOT NULL,
     category_id VARCHAR(255),
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INT NOT NULL DEFAULT 0,
+    business_ratio INT, -- 支出の事業割合の既定値（%）。NULLの場合は既定値なし
+    version INT NOT NULL DEFAULT 1, -- 楽観的排他制御のため更新のたびに1増やす
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (id),
+    FULLTEXT KEY ft_asset_name (name) WITH PARSER ngram,
     CONSTRAINT fk_asset_user FOREIGN KEY (user_id) REFERENCES user(id),
     CONSTRAINT fk_asset_category FOREIGN KEY (category_id) REFERENCES asset_category(id) ON DELETE SET NULL
 );
@@ -36,6 +44,44 @@ CREATE TABLE IF NOT EXISTS record_type (
     PRIMARY KEY (name)
 );
 
+CREATE TABLE IF NOT EXISTS record_category (
+    id VARCHAR(255),
+    user_id VARCHAR(255) NOT NULL,
+    record_type VARCHAR(32) NOT NULL, -- 収入・支出ごとに別の分類木を持つ
+    name VARCHAR(255) NOT NULL,
+    parent_id VARCHAR(255),
+    expense_account VARCHAR(32), -- 青色申告決算書の科目。NULLの場合は親の分類の科目を使う
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_record_category_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_record_category_record_type FOREIGN KEY (record_type) REFERENCES record_type(name),
+    CONSTRAINT fk_record_category_parent FOREIGN KEY (parent_id) REFERENCES record_category(id)
+);
+
+CREATE TABLE IF NOT EXISTS payee (
+    id VARCHAR(255),
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE (user_id, name), -- 同時に作成・更新された場合でも同じ名前のPayeeができないようにする
+    CONSTRAINT fk_payee_user FOREIGN KEY (user_id) REFERENCES user(id)
+);
+
+CREATE TABLE IF NOT EXISTS payee_alias (
+    user_id VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL, -- 表記ゆれを無視して照合するため、全角・半角やカタカナ・ひらがなを統一した別名
+    payee_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, normalized_name),
+    CONSTRAINT fk_payee_alias_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_payee_alias_payee FOREIGN KEY (payee_id) REFERENCES payee(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS record (
     id VARCHAR(255),
     user_id VARCHAR(255) NOT NULL,
@@ -43,11 +89,21 @@ CREATE TABLE IF NOT EXISTS record (
     title TEXT NOT NULL,
     description TEXT NOT NULL,
     at TIMESTAMP NOT NULL,
+    category_id VARCHAR(255),
+    payee_id VARCHAR(255),
+    tax_rate VARCHAR(32), -- STANDARD（10%）・REDUCED（8%）。NULLの場合は課税対象外
+    tax_mode VARCHAR(32) NOT NULL DEFAULT 'INCLUSIVE', -- 明細の単価が税込（INCLUSIVE）か税抜（EXCLUSIVE）か
+    tax_rounding VARCHAR(32) NOT NULL DEFAULT 'FLOOR', -- 税率・明細を設定した時点のユーザーの端数処理
+    business_ratio INT, -- 事業割合（%）。NULLの場合はタグ・資産の既定値を使う
+    version INT NOT NULL DEFAULT 1, -- 楽観的排他制御のため更新のたびに1増やす
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (id),
+    FULLTEXT KEY ft_record_title_description (title, description) WITH PARSER ngram, -- 日本語を検索できるようにngramパーサーを使う
     CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES user(id), -- synbolを命名しないとmysqldefがエラーになる
-    CONSTRAINT fk_record_type FOREIGN KEY (record_type) REFERENCES record_type(name)
+    CONSTRAINT fk_record_type FOREIGN KEY (record_type) REFERENCES record_type(name),
+    CONSTRAINT fk_record_category FOREIGN KEY (category_id) REFERENCES record_category(id) ON DELETE SET NULL,
+    CONSTRAINT fk_record_payee FOREIGN KEY (payee_id) REFERENCES payee(id) ON DELETE SET NULL
 );
 
 CREATE TABLE IF NOT EXISTS asset_change (
@@ -68,10 +124,17 @@ CREATE TABLE IF NOT EXISTS tag (
     id VARCHAR(255),
     user_id VARCHAR(255) NOT NULL,
     name VARCHAR(255) NOT NULL,
+    color VARCHAR(7), -- #RRGGBB形式
+    icon VARCHAR(64), -- 絵文字またはアイコンのキー
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INT NOT NULL DEFAULT 0,
+    business_ratio INT, -- このタグを付与した支出の事業割合の既定値（%）
+    version INT NOT NULL DEFAULT 1, -- 楽観的排他制御のため更新のたびに1増やす
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     PRIMARY KEY (id),
     UNIQUE (user_id, name),
+    FULLTEXT KEY ft_tag_name (name) WITH PARSER ngram,
     CONSTRAINT fk_tag_user FOREIGN KEY (user_id) REFERENCES user(id)
 );
 
@@ -85,6 +148,34 @@ CREATE TABLE IF NOT EXISTS record_tag (
     CONSTRAINT fk_tag FOREIGN KEY (tag_id) REFERENCES tag(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS record_item (
+    id VARCHAR(255),
+    user_id VARCHAR(255) NOT NULL,
+    record_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    unit_price INT NOT NULL, -- 値引きの行は負の値
+    tax_rate VARCHAR(32) NOT NULL, -- STANDARD（10%）またはREDUCED（8%）
+    inclusive_amount INT NOT NULL DEFAULT 0, -- 税込金額。単価が税抜の場合は税率ごとの消費税額を割り振った額
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_record_item_record (record_id, sort_order),
+    CONSTRAINT fk_record_item_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_record_item_record FOREIGN KEY (record_id) REFERENCES record(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS record_item_tag (
+    record_item_id VARCHAR(255) NOT NULL,
+    tag_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (record_item_id, tag_id),
+    CONSTRAINT fk_record_item_tag_item FOREIGN KEY (record_item_id) REFERENCES record_item(id) ON DELETE CASCADE,
+    CONSTRAINT fk_record_item_tag_tag FOREIGN KEY (tag_id) REFERENCES tag(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS total_assets_snapshot (
     id VARCHAR(255),
     user_id VARCHAR(255) NOT NULL,
@@ -97,4 +188,56 @@ CREATE TABLE IF NOT EXISTS total_assets_snapshot (
     PRIMARY KEY (id),
     CONSTRAINT fk_total_assets_snapshot_user FOREIGN KEY (user_id) REFERENCES user(id),
     CONSTRAINT fk_total_assets_snapshot_asset FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE SET NULL
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    user_id VARCHAR(255) NOT NULL,
+    `key` VARCHAR(255) NOT NULL, -- クライアントが作成操作ごとに生成するキー
+    operation VARCHAR(64) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL, -- 最初の操作で作成したリソースのID
+    request_hash CHAR(64) NOT NULL DEFAULT '', -- 最初の操作の入力のSHA-256
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, `key`),
+    CONSTRAINT fk_idempotency_key_user FOREIGN KEY (user_id) REFERENCES user(id)
+);
+
+CREATE TABLE IF NOT EXISTS attachment (
+    id VARCHAR(255),
+    user_id VARCHAR(255) NOT NULL,
+    record_id VARCHAR(255), -- Recordを削除するとNULLになり、定期的な掃除でファイルとあわせて削除する
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(512) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_attachment_user (user_id, size),
+    CONSTRAINT fk_attachment_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_attachment_record FOREIGN KEY (record_id) REFERENCES record(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS goal (
+    id VARCHAR(255),
+    user_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    target_amount INT NOT NULL,
+    target_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- 進捗の計画はこの時点の残高から始める
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_goal_user FOREIGN KEY (user_id) REFERENCES user(id)
+);
+
+CREATE TABLE IF NOT EXISTS goal_asset (
+    user_id VARCHAR(255) NOT NULL,
+    goal_id VARCHAR(255) NOT NULL,
+    asset_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (goal_id, asset_id),
+    CONSTRAINT fk_goal_asset_user FOREIGN KEY (user_id) REFERENCES user(id),
+    CONSTRAINT fk_goal_asset_goal FOREIGN KEY (goal_id) REFERENCES goal(id) ON DELETE CASCADE,
+    CONSTRAINT fk_goal_asset_asset FOREIGN KEY (asset_id) REFERENCES asset(id) ON DELETE CASCADE -- Assetを削除すると目標から外れる
+);
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"kakeibo-web-server/domain"
+	"strconv"
 
 	"github.com/gocraft/dbr/v2"
 	"golang.org/x/xerrors"
@@ -23,7 +24,7 @@ func NewAssetRepository(sess *dbr.Session) *AssetRepository {
 
 func (r *AssetRepository) Insert(ctx context.Context, asset *domain.Asset) (*domain.Asset, error) {
 	runner := getRunner(ctx, r.sess)
-	_, err := runner.InsertInto(assettableName).Columns("id", "user_id", "name", "category_id").Record(asset).Exec()
+	_, err := runner.InsertInto(assettableName).Columns("id", "user_id", "name", "category_id", "archived", "sort_order", "business_ratio").Record(asset).Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to insert asset: %w", err)
 	}
@@ -36,7 +37,9 @@ func (r *AssetRepository) Update(ctx context.Context, asset *domain.Asset) (*dom
 	result, err := runner.Update(assettableName).
 		Set("name", asset.Name).
 		Set("category_id", asset.CategoryID).
-		Where("id = ? AND user_id = ?", asset.ID, asset.UserID).
+		Set("business_ratio", asset.BusinessRatio).
+		Set("version", incrementVersion).
+		Where("id = ? AND user_id = ? AND version = ?", asset.ID, asset.UserID, asset.Version).
 		Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to update asset: %w", err)
@@ -46,8 +49,9 @@ func (r *AssetRepository) Update(ctx context.Context, asset *domain.Asset) (*dom
 		return nil, xerrors.Errorf("failed to get affected rows: %w", err)
 	}
 	if resultCount == 0 {
-		return nil, domain.ErrEntityNotFound
+		return nil, xerrors.Errorf("asset %s version %d: %w", asset.ID, asset.Version, domain.ErrConflict)
 	}
+	asset.Version++
 
 	return asset, nil
 }
@@ -70,6 +74,71 @@ func (r *AssetRepository) Delete(ctx context.Context, asset *domain.Asset) (doma
 	return asset.ID, nil
 }
 
+func (r *AssetRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.AssetID) (*domain.Asset, error) {
+	runner := getRunner(ctx, r.sess)
+	asset := &domain.Asset{}
+
+	err := runner.Select("*").From(assettableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		LoadOneContext(ctx, asset)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get asset by ID: %w", err)
+	}
+
+	return asset, nil
+}
+
+func (r *AssetRepository) UpdateArchived(ctx context.Context, userID domain.UserID, id domain.AssetID, archived bool) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(assettableName).
+		Set("archived", archived).
+		Set("version", incrementVersion).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to update archived of asset: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AssetRepository) UpdateSortOrder(ctx context.Context, userID domain.UserID, id domain.AssetID, sortOrder int) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(assettableName).
+		Set("sort_order", sortOrder).
+		Set("version", incrementVersion).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to update sort order of asset: %w", err)
+	}
+
+	return nil
+}
+
+// ShiftSortOrderExcludingIDs は並び替えで指定されなかったAssetの並び順を、offsetより後ろに現在の順のまま移す
+func (r *AssetRepository) ShiftSortOrderExcludingIDs(ctx context.Context, userID domain.UserID, excludeIDs []domain.AssetID, offset int) error {
+	return shiftSortOrderExcludingIDs(ctx, getRunner(ctx, r.sess), assettableName, userID, excludeIDs, offset)
+}
+
+// GetMaxSortOrderByUserID はユーザーのAssetの並び順の最大値を返す。Assetがない場合は0を返す
+func (r *AssetRepository) GetMaxSortOrderByUserID(ctx context.Context, userID domain.UserID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var maxSortOrder int
+	err := runner.Select("COALESCE(MAX(sort_order), 0)").From(assettableName).
+		Where("user_id = ?", userID).
+		LoadOneContext(ctx, &maxSortOrder)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get max sort order of assets: %w", err)
+	}
+
+	return maxSortOrder, nil
+}
+
 func (r *AssetRepository) List(ctx context.Context, userID domain.UserID) ([]*domain.Asset, error) {
 	runner := getRunner(ctx, r.sess)
 	assets := make([]*domain.Asset, 0)
@@ -84,7 +153,8 @@ func (r *AssetRepository) List(ctx context.Context, userID domain.UserID) ([]*do
 	return assets, nil
 }
 
-func (r *AssetRepository) GetMultiByUserIDAndCategoryID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, categoryID *domain.AssetCategoryID) ([]*domain.Asset, *domain.PageInfo, error) {
+// GetMultiByUserIDAndCategoryID はユーザーのAssetを取得する。includeArchivedがfalseの場合はアーカイブしたAssetを除く
+func (r *AssetRepository) GetMultiByUserIDAndCategoryID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, categoryID *domain.AssetCategoryID, includeArchived bool) ([]*domain.Asset, *domain.PageInfo, error) {
 	runner := getRunner(ctx, r.sess)
 	assets := make([]*domain.Asset, 0)
 
@@ -93,8 +163,16 @@ func (r *AssetRepository) GetMultiByUserIDAndCategoryID(ctx context.Context, pag
 	if categoryID != nil {
 		stmt = stmt.Where("category_id = ?", *categoryID)
 	}
+	if !includeArchived {
+		stmt = stmt.Where("archived = ?", false)
+	}
+
+	sortColumn, err := assetSortColumn(domain.AssetSortKey(pageParam.SortKey))
+	if err != nil {
+		return nil, nil, xerrors.Errorf(": %w", err)
+	}
 
-	stmt, err := paginate(pageParam, stmt)
+	stmt, err = paginateByColumn(pageParam, stmt, sortColumn, "id")
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to paginate: %w", err)
 	}
@@ -114,6 +192,9 @@ func (r *AssetRepository) GetMultiByUserIDAndCategoryID(ctx context.Context, pag
 		case domain.AssetCategorySortKeyCreatedAt.String():
 			startCursor = domain.NewPageCursor(string(assets[0].ID), assets[0].CreatedAt.Format("2006-01-02 15:04:05"))
 			endCursor = domain.NewPageCursor(string(assets[len(assets)-1].ID), assets[len(assets)-1].CreatedAt.Format("2006-01-02 15:04:05"))
+		case domain.AssetSortKeyManual.String():
+			startCursor = domain.NewPageCursor(string(assets[0].ID), strconv.Itoa(assets[0].SortOrder))
+			endCursor = domain.NewPageCursor(string(assets[len(assets)-1].ID), strconv.Itoa(assets[len(assets)-1].SortOrder))
 		default:
 			panic("unsupported sort key for asset repository")
 		}
@@ -131,6 +212,27 @@ func (r *AssetRepository) GetMultiByUserIDAndCategoryID(ctx context.Context, pag
 	return assets, pageInfo, nil
 }
 
+func (r *AssetRepository) CountByUserIDAndCategoryID(ctx context.Context, userID domain.UserID, categoryID *domain.AssetCategoryID, includeArchived bool) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	stmt := runner.Select("COUNT(*)").From(assettableName).Where("user_id = ?", userID)
+
+	if categoryID != nil {
+		stmt = stmt.Where("category_id = ?", *categoryID)
+	}
+	if !includeArchived {
+		stmt = stmt.Where("archived = ?", false)
+	}
+
+	var count int
+	err := stmt.LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count assets by userID: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *AssetRepository) GetMultiByUserIDAndIDs(ctx context.Context, userID domain.UserID, assetIDs []domain.AssetID) ([]*domain.Asset, error) {
 	runner := getRunner(ctx, r.sess)
 	assets := make([]*domain.Asset, 0)
@@ -174,3 +276,36 @@ func (r *AssetRepository) GetMultiByCategoryIDs(ctx context.Context, userID doma
 
 	return assets, nil
 }
+
+// UpdateCategoryIDByCategoryID はカテゴリに属するAssetをnewCategoryIDのカテゴリに移動し、移動した件数を返す
+// newCategoryIDがnilの場合はカテゴリを未設定にする
+func (r *AssetRepository) UpdateCategoryIDByCategoryID(ctx context.Context, userID domain.UserID, categoryID domain.AssetCategoryID, newCategoryID *domain.AssetCategoryID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.Update(assettableName).
+		Set("category_id", newCategoryID).
+		Set("version", incrementVersion).
+		Where("user_id = ? AND category_id = ?", userID, categoryID).
+		Exec()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to update category of assets: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func assetSortColumn(sortKey domain.AssetSortKey) (string, error) {
+	switch sortKey {
+	case domain.AssetSortKeyName:
+		return "name", nil
+	case domain.AssetSortKeyCreatedAt:
+		return "created_at", nil
+	case domain.AssetSortKeyManual:
+		return "sort_order", nil
+	default:
+		return "", xerrors.Errorf("unsupported sort key for asset %s: %w", sortKey, domain.ErrInvalidPageParam)
+	}
+}
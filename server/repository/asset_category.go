@@ -21,7 +21,7 @@ func NewAssetCategoryRepository(sess *dbr.Session) *AssetCategoryRepository {
 
 func (r *AssetCategoryRepository) Insert(ctx context.Context, category *domain.AssetCategory) (*domain.AssetCategory, error) {
 	runner := getRunner(ctx, r.sess)
-	_, err := runner.InsertInto("asset_category").Columns("id", "user_id", "name").Record(category).Exec()
+	_, err := runner.InsertInto("asset_category").Columns("id", "user_id", "name", "parent_id").Record(category).Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to insert asset category: %w", err)
 	}
@@ -50,6 +50,33 @@ func (r *AssetCategoryRepository) Update(ctx context.Context, category *domain.A
 	return category, nil
 }
 
+func (r *AssetCategoryRepository) UpdateParentID(ctx context.Context, category *domain.AssetCategory) (*domain.AssetCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update("asset_category").
+		Set("parent_id", category.ParentID).
+		Where("id = ? AND user_id = ?", category.ID, category.UserID).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to update parent of asset category: %w", err)
+	}
+
+	return category, nil
+}
+
+// UpdateParentIDByParentID は子のカテゴリの親を付け替える
+func (r *AssetCategoryRepository) UpdateParentIDByParentID(ctx context.Context, userID domain.UserID, parentID domain.AssetCategoryID, newParentID *domain.AssetCategoryID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update("asset_category").
+		Set("parent_id", newParentID).
+		Where("user_id = ? AND parent_id = ?", userID, parentID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to update parent of asset categories: %w", err)
+	}
+
+	return nil
+}
+
 func (r *AssetCategoryRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.AssetCategoryID) (*domain.AssetCategory, error) {
 	runner := getRunner(ctx, r.sess)
 	category := &domain.AssetCategory{}
@@ -65,11 +92,15 @@ func (r *AssetCategoryRepository) GetByID(ctx context.Context, userID domain.Use
 	return category, nil
 }
 
-func (r *AssetCategoryRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID) ([]*domain.AssetCategory, *domain.PageInfo, error) {
+// GetMultiByUserID はユーザーのカテゴリを取得する。rootOnlyがtrueの場合は最上位のカテゴリのみを取得する
+func (r *AssetCategoryRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, rootOnly bool) ([]*domain.AssetCategory, *domain.PageInfo, error) {
 	runner := getRunner(ctx, r.sess)
 	categories := make([]*domain.AssetCategory, 0)
 
 	stmt := runner.Select("*").From("asset_category").Where("user_id = ?", userID)
+	if rootOnly {
+		stmt.Where("parent_id IS NULL")
+	}
 
 	stmt, err := paginate(pageParam, stmt)
 	if err != nil {
@@ -111,10 +142,31 @@ func (r *AssetCategoryRepository) GetMultiByUserID(ctx context.Context, pagePara
 	return categories, pageInfo, nil
 }
 
+func (r *AssetCategoryRepository) CountByUserID(ctx context.Context, userID domain.UserID, rootOnly bool) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	stmt := runner.Select("COUNT(*)").From("asset_category").Where("user_id = ?", userID)
+	if rootOnly {
+		stmt.Where("parent_id IS NULL")
+	}
+
+	var count int
+	err := stmt.LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count asset categories by userID: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *AssetCategoryRepository) GetMultiByAssetCategoryIDs(ctx context.Context, userID domain.UserID, assetCategoryIDs []domain.AssetCategoryID) ([]*domain.AssetCategory, error) {
-	categories := make([]*domain.AssetCategory, 0)
+	categories := make([]*domain.AssetCategory, 0, len(assetCategoryIDs))
+	if len(assetCategoryIDs) == 0 {
+		return categories, nil
+	}
 
-	stmt := r.sess.Select("*").From("asset_category").
+	runner := getRunner(ctx, r.sess)
+	stmt := runner.Select("*").From("asset_category").
 		Where("user_id = ?", userID).
 		Where("id IN ?", assetCategoryIDs)
 
@@ -126,6 +178,53 @@ func (r *AssetCategoryRepository) GetMultiByAssetCategoryIDs(ctx context.Context
 	return categories, nil
 }
 
+// ListByUserID はユーザーのすべてのカテゴリを取得する。木構造をたどるために使う
+func (r *AssetCategoryRepository) ListByUserID(ctx context.Context, userID domain.UserID) (domain.AssetCategories, error) {
+	runner := getRunner(ctx, r.sess)
+	categories := make([]*domain.AssetCategory, 0)
+
+	_, err := runner.Select("*").From("asset_category").Where("user_id = ?", userID).LoadContext(ctx, &categories)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list asset categories by userID: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *AssetCategoryRepository) GetMultiByParentIDs(ctx context.Context, userID domain.UserID, parentIDs []domain.AssetCategoryID) ([]*domain.AssetCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	categories := make([]*domain.AssetCategory, 0)
+
+	if len(parentIDs) == 0 {
+		return categories, nil
+	}
+
+	_, err := runner.Select("*").From("asset_category").
+		Where("user_id = ? AND parent_id IN ?", userID, parentIDs).
+		OrderAsc("name").
+		OrderAsc("id").
+		LoadContext(ctx, &categories)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get asset categories by parent IDs: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *AssetCategoryRepository) CountByParentID(ctx context.Context, userID domain.UserID, parentID domain.AssetCategoryID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var count int
+	err := runner.Select("COUNT(*)").From("asset_category").
+		Where("user_id = ? AND parent_id = ?", userID, parentID).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count asset categories by parent ID: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *AssetCategoryRepository) Delete(ctx context.Context, userID domain.UserID, assetCategoryID domain.AssetCategoryID) (domain.AssetCategoryID, error) {
 	runner := getRunner(ctx, r.sess)
 
@@ -48,6 +48,18 @@ func (r *AssetChangeRepository) Update(ctx context.Context, change *domain.Asset
 	return change, nil
 }
 
+func (r *AssetChangeRepository) Delete(ctx context.Context, userID domain.UserID, id domain.AssetChangeID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(assetChangeTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete asset change: %w", err)
+	}
+
+	return nil
+}
+
 func (r *AssetChangeRepository) GetMultiByRecordID(ctx context.Context, userID domain.UserID, recordID domain.RecordID) (domain.AssetChanges, error) {
 	runner := getRunner(ctx, r.sess)
 	changes := make([]*domain.AssetChange, 0)
@@ -108,3 +120,75 @@ func (r *AssetChangeRepository) GetMultiWithAtByAssetIDAndAfterAndBefore(ctx con
 
 	return changes, nil
 }
+
+// GetMultiWithAtByAssetIDAndAtRange はAssetのAssetChangeのうち、RecordのAtがfrom以上to未満のものを日時順に取得する
+func (r *AssetChangeRepository) GetMultiWithAtByAssetIDAndAtRange(ctx context.Context, userID domain.UserID, assetID domain.AssetID, from, to time.Time) (domain.AssetChangeWithAts, error) {
+	runner := getRunner(ctx, r.sess)
+	changes := make([]*domain.AssetChangeWithAt, 0)
+
+	_, err := runner.Select("ac.*, rc.at").
+		From(dbr.I(assetChangeTableName).As("ac")).
+		Join(dbr.I("record").As("rc"), "rc.id = ac.record_id").
+		Where("ac.user_id = ? AND ac.asset_id = ?", userID, assetID).
+		Where("rc.at >= ? AND rc.at < ?", from, to).
+		OrderAsc("rc.at").
+		OrderAsc("ac.id").
+		LoadContext(ctx, &changes)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get asset changes by asset ID and at range: %w", err)
+	}
+
+	return changes, nil
+}
+
+// assetCategoryAmount はカテゴリごとのAssetChangeの合計
+type assetCategoryAmount struct {
+	CategoryID domain.AssetCategoryID
+	Amount     int
+}
+
+// SumAmountGroupByAssetCategoryID はカテゴリに属するAssetの、before より前のRecordによる残高をカテゴリごとに合計する
+// カテゴリに属さないAssetは含まない
+func (r *AssetChangeRepository) SumAmountGroupByAssetCategoryID(ctx context.Context, userID domain.UserID, before time.Time) (map[domain.AssetCategoryID]int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	amounts := make([]*assetCategoryAmount, 0)
+	_, err := runner.Select("a.category_id", "SUM(ac.amount) AS amount").From(dbr.I(assetChangeTableName).As("ac")).
+		Join(dbr.I(assettableName).As("a"), "a.id = ac.asset_id").
+		Join(dbr.I(recordTableName).As("rc"), "rc.id = ac.record_id").
+		Where("ac.user_id = ?", userID).
+		Where("a.category_id IS NOT NULL").
+		Where("rc.at < ?", before).
+		GroupBy("a.category_id").
+		LoadContext(ctx, &amounts)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sum asset change amount group by asset category ID: %w", err)
+	}
+
+	result := make(map[domain.AssetCategoryID]int, len(amounts))
+	for _, amount := range amounts {
+		result[amount.CategoryID] = amount.Amount
+	}
+
+	return result, nil
+}
+
+// OptionalGetFirstAtByAssetID はAssetの最も古い履歴（Record）の日時を返す。履歴がない場合はnilを返す
+func (r *AssetChangeRepository) OptionalGetFirstAtByAssetID(ctx context.Context, userID domain.UserID, assetID domain.AssetID) (*time.Time, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var firstAt dbr.NullTime
+	err := runner.Select("MIN(rc.at)").From(dbr.I(assetChangeTableName).As("ac")).
+		Join(dbr.I("record").As("rc"), "rc.id = ac.record_id").
+		Where("ac.user_id = ?", userID).
+		Where("ac.asset_id = ?", assetID).
+		LoadOneContext(ctx, &firstAt)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get first asset change by asset ID: %w", err)
+	}
+	if !firstAt.Valid {
+		return nil, nil
+	}
+
+	return &firstAt.Time, nil
+}
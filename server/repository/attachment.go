@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const attachmentTableName = "attachment"
+
+type AttachmentRepository struct {
+	sess *dbr.Session
+}
+
+func NewAttachmentRepository(sess *dbr.Session) *AttachmentRepository {
+	return &AttachmentRepository{
+		sess: sess,
+	}
+}
+
+func (r *AttachmentRepository) Insert(ctx context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertInto(attachmentTableName).
+		Columns("id", "user_id", "record_id", "file_name", "content_type", "size", "storage_key").
+		Record(attachment).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to insert attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.AttachmentID) (*domain.Attachment, error) {
+	runner := getRunner(ctx, r.sess)
+	attachment := &domain.Attachment{}
+
+	err := runner.Select("*").From(attachmentTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		LoadOneContext(ctx, attachment)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get attachment by ID: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) GetMultiByRecordIDs(ctx context.Context, userID domain.UserID, recordIDs []domain.RecordID) ([]*domain.Attachment, error) {
+	runner := getRunner(ctx, r.sess)
+	attachments := make([]*domain.Attachment, 0)
+
+	if len(recordIDs) == 0 {
+		return attachments, nil
+	}
+
+	_, err := runner.Select("*").From(attachmentTableName).
+		Where("user_id = ? AND record_id IN ?", userID, recordIDs).
+		OrderAsc("created_at").
+		OrderAsc("id").
+		LoadContext(ctx, &attachments)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get attachments by record IDs: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// SumSizeByUserID はRecordに添付されているファイルの合計サイズを返す
+func (r *AttachmentRepository) SumSizeByUserID(ctx context.Context, userID domain.UserID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var sum int
+	err := runner.Select("COALESCE(SUM(size), 0)").From(attachmentTableName).
+		Where("user_id = ? AND record_id IS NOT NULL", userID).
+		LoadOneContext(ctx, &sum)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to sum attachment size: %w", err)
+	}
+
+	return sum, nil
+}
+
+// GetMultiOrphaned は削除されたRecordに添付されていたファイルをlimit件まで取得する
+func (r *AttachmentRepository) GetMultiOrphaned(ctx context.Context, limit uint64) ([]*domain.Attachment, error) {
+	runner := getRunner(ctx, r.sess)
+	attachments := make([]*domain.Attachment, 0)
+
+	_, err := runner.Select("*").From(attachmentTableName).
+		Where("record_id IS NULL").
+		OrderAsc("updated_at").
+		Limit(limit).
+		LoadContext(ctx, &attachments)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get orphaned attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, userID domain.UserID, id domain.AttachmentID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(attachmentTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return nil
+}
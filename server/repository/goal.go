@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	goalTableName      = "goal"
+	goalAssetTableName = "goal_asset"
+)
+
+type GoalRepository struct {
+	sess *dbr.Session
+}
+
+func NewGoalRepository(sess *dbr.Session) *GoalRepository {
+	return &GoalRepository{
+		sess: sess,
+	}
+}
+
+// goalAsset はgoal_assetテーブルの行
+type goalAsset struct {
+	GoalID  domain.GoalID
+	AssetID domain.AssetID
+}
+
+func (r *GoalRepository) Insert(ctx context.Context, goal *domain.Goal) (*domain.Goal, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertInto(goalTableName).
+		Columns("id", "user_id", "name", "target_amount", "target_date").
+		Record(goal).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to insert goal: %w", err)
+	}
+
+	err = r.insertAssets(ctx, goal)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+// Update は目標の内容を更新し、紐付けるAssetをすべて置き換える
+func (r *GoalRepository) Update(ctx context.Context, goal *domain.Goal) (*domain.Goal, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(goalTableName).
+		Set("name", goal.Name).
+		Set("target_amount", goal.TargetAmount).
+		Set("target_date", goal.TargetDate).
+		Where("id = ? AND user_id = ?", goal.ID, goal.UserID).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to update goal: %w", err)
+	}
+
+	_, err = runner.DeleteFrom(goalAssetTableName).
+		Where("goal_id = ? AND user_id = ?", goal.ID, goal.UserID).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to delete goal assets: %w", err)
+	}
+
+	err = r.insertAssets(ctx, goal)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+func (r *GoalRepository) insertAssets(ctx context.Context, goal *domain.Goal) error {
+	if len(goal.AssetIDs) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	stmt := runner.InsertInto(goalAssetTableName).Columns("user_id", "goal_id", "asset_id")
+	for _, assetID := range goal.AssetIDs {
+		stmt = stmt.Values(goal.UserID, goal.ID, assetID)
+	}
+
+	_, err := stmt.Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to insert goal assets: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GoalRepository) Delete(ctx context.Context, userID domain.UserID, id domain.GoalID) error {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.DeleteFrom(goalTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete goal: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+	if count == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *GoalRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.GoalID) (*domain.Goal, error) {
+	runner := getRunner(ctx, r.sess)
+	goal := &domain.Goal{}
+
+	err := runner.Select("*").From(goalTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		LoadOneContext(ctx, goal)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get goal by ID: %w", err)
+	}
+
+	err = r.loadAssetIDs(ctx, userID, domain.Goals{goal})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+func (r *GoalRepository) GetMultiByIDs(ctx context.Context, userID domain.UserID, ids []domain.GoalID) (domain.Goals, error) {
+	runner := getRunner(ctx, r.sess)
+	goals := make([]*domain.Goal, 0, len(ids))
+
+	if len(ids) == 0 {
+		return goals, nil
+	}
+
+	_, err := runner.Select("*").From(goalTableName).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		LoadContext(ctx, &goals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get goals by IDs: %w", err)
+	}
+
+	err = r.loadAssetIDs(ctx, userID, goals)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goals, nil
+}
+
+// ListByUserID はユーザーのすべての目標を期限の近い順に取得する
+func (r *GoalRepository) ListByUserID(ctx context.Context, userID domain.UserID) (domain.Goals, error) {
+	runner := getRunner(ctx, r.sess)
+	goals := make([]*domain.Goal, 0)
+
+	_, err := runner.Select("*").From(goalTableName).
+		Where("user_id = ?", userID).
+		OrderAsc("target_date").
+		OrderAsc("id").
+		LoadContext(ctx, &goals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list goals: %w", err)
+	}
+
+	err = r.loadAssetIDs(ctx, userID, goals)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goals, nil
+}
+
+// loadAssetIDs は目標に紐付けたAssetのIDを取得して設定する
+func (r *GoalRepository) loadAssetIDs(ctx context.Context, userID domain.UserID, goals domain.Goals) error {
+	if len(goals) == 0 {
+		return nil
+	}
+
+	ids := make([]domain.GoalID, 0, len(goals))
+	for _, goal := range goals {
+		ids = append(ids, goal.ID)
+	}
+
+	runner := getRunner(ctx, r.sess)
+	goalAssets := make([]*goalAsset, 0)
+	_, err := runner.Select("goal_id", "asset_id").From(goalAssetTableName).
+		Where("user_id = ? AND goal_id IN ?", userID, ids).
+		OrderAsc("created_at").
+		OrderAsc("asset_id").
+		LoadContext(ctx, &goalAssets)
+	if err != nil {
+		return xerrors.Errorf("failed to get goal assets: %w", err)
+	}
+
+	assetIDMap := make(map[domain.GoalID][]domain.AssetID, len(goals))
+	for _, goalAsset := range goalAssets {
+		assetIDMap[goalAsset.GoalID] = append(assetIDMap[goalAsset.GoalID], goalAsset.AssetID)
+	}
+	for _, goal := range goals {
+		goal.AssetIDs = assetIDMap[goal.ID]
+		if goal.AssetIDs == nil {
+			goal.AssetIDs = make([]domain.AssetID, 0)
+		}
+	}
+
+	return nil
+}
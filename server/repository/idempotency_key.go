@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const idempotencyKeyTableName = "idempotency_key"
+
+type IdempotencyKeyRepository struct {
+	sess *dbr.Session
+}
+
+func NewIdempotencyKeyRepository(sess *dbr.Session) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		sess: sess,
+	}
+}
+
+// Insert は冪等キーを保存する。同じキーがすでにある場合はErrIdempotencyKeyExistsを返す
+// 同じキーを別のトランザクションが保存中の場合は、そのトランザクションが終わるまで待つ
+func (r *IdempotencyKeyRepository) Insert(ctx context.Context, key *domain.IdempotencyKey) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertInto(idempotencyKeyTableName).
+		Columns("user_id", "key", "operation", "resource_id", "request_hash").
+		Record(key).
+		Exec()
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return domain.ErrIdempotencyKeyExists
+		}
+		return xerrors.Errorf("failed to insert idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) GetByKey(ctx context.Context, userID domain.UserID, key string) (*domain.IdempotencyKey, error) {
+	runner := getRunner(ctx, r.sess)
+	idempotencyKey := &domain.IdempotencyKey{}
+
+	err := runner.Select("*").From(idempotencyKeyTableName).
+		Where("user_id = ? AND `key` = ?", userID, key).
+		LoadOneContext(ctx, idempotencyKey)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return idempotencyKey, nil
+}
+
+// DeleteExpiredByUserID は保持期間を過ぎたユーザーの冪等キーを削除する
+func (r *IdempotencyKeyRepository) DeleteExpiredByUserID(ctx context.Context, userID domain.UserID, before time.Time) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(idempotencyKeyTableName).
+		Where("user_id = ? AND created_at < ?", userID, before).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	payeeTableName      = "payee"
+	payeeAliasTableName = "payee_alias"
+)
+
+type PayeeRepository struct {
+	sess *dbr.Session
+}
+
+func NewPayeeRepository(sess *dbr.Session) *PayeeRepository {
+	return &PayeeRepository{
+		sess: sess,
+	}
+}
+
+// payeeAlias はpayee_aliasテーブルの行
+type payeeAlias struct {
+	PayeeID domain.PayeeID
+	Name    string
+}
+
+func (r *PayeeRepository) Insert(ctx context.Context, payee *domain.Payee) (*domain.Payee, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertInto(payeeTableName).
+		Columns("id", "user_id", "name").
+		Record(payee).
+		Exec()
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, xerrors.Errorf("payee %s: %w", payee.Name, domain.ErrPayeeNameConflict)
+		}
+		return nil, xerrors.Errorf("failed to insert payee: %w", err)
+	}
+
+	err = r.insertAliases(ctx, payee)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// Update は名前を更新し、別名をすべて置き換える
+func (r *PayeeRepository) Update(ctx context.Context, payee *domain.Payee) (*domain.Payee, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(payeeTableName).
+		Set("name", payee.Name).
+		Where("id = ? AND user_id = ?", payee.ID, payee.UserID).
+		Exec()
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, xerrors.Errorf("payee %s: %w", payee.Name, domain.ErrPayeeNameConflict)
+		}
+		return nil, xerrors.Errorf("failed to update payee: %w", err)
+	}
+
+	_, err = runner.DeleteFrom(payeeAliasTableName).
+		Where("payee_id = ? AND user_id = ?", payee.ID, payee.UserID).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to delete payee aliases: %w", err)
+	}
+
+	err = r.insertAliases(ctx, payee)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// insertAliases は別名を保存する。正規化した別名が他のPayeeと重複する場合はErrPayeeNameConflictを返す
+func (r *PayeeRepository) insertAliases(ctx context.Context, payee *domain.Payee) error {
+	if len(payee.Aliases) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	stmt := runner.InsertInto(payeeAliasTableName).Columns("user_id", "normalized_name", "payee_id", "name")
+	for _, alias := range payee.Aliases {
+		stmt = stmt.Values(payee.UserID, domain.NormalizePayeeName(alias), payee.ID, alias)
+	}
+
+	_, err := stmt.Exec()
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return xerrors.Errorf("alias of payee %s: %w", payee.Name, domain.ErrPayeeNameConflict)
+		}
+		return xerrors.Errorf("failed to insert payee aliases: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PayeeRepository) Delete(ctx context.Context, userID domain.UserID, id domain.PayeeID) error {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.DeleteFrom(payeeTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete payee: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+	if count == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *PayeeRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.PayeeID) (*domain.Payee, error) {
+	runner := getRunner(ctx, r.sess)
+	payee := &domain.Payee{}
+
+	err := runner.Select("*").From(payeeTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		LoadOneContext(ctx, payee)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get payee by ID: %w", err)
+	}
+
+	err = r.loadAliases(ctx, userID, domain.Payees{payee})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+func (r *PayeeRepository) GetMultiByIDs(ctx context.Context, userID domain.UserID, ids []domain.PayeeID) (domain.Payees, error) {
+	runner := getRunner(ctx, r.sess)
+	payees := make([]*domain.Payee, 0, len(ids))
+
+	if len(ids) == 0 {
+		return payees, nil
+	}
+
+	_, err := runner.Select("*").From(payeeTableName).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		LoadContext(ctx, &payees)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get payees by IDs: %w", err)
+	}
+
+	err = r.loadAliases(ctx, userID, payees)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees, nil
+}
+
+// ListByUserID はユーザーのすべてのPayeeを名前順に取得する
+func (r *PayeeRepository) ListByUserID(ctx context.Context, userID domain.UserID) (domain.Payees, error) {
+	runner := getRunner(ctx, r.sess)
+	payees := make([]*domain.Payee, 0)
+
+	_, err := runner.Select("*").From(payeeTableName).
+		Where("user_id = ?", userID).
+		OrderAsc("name").
+		OrderAsc("id").
+		LoadContext(ctx, &payees)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list payees: %w", err)
+	}
+
+	err = r.loadAliases(ctx, userID, payees)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees, nil
+}
+
+// loadAliases はPayeeの別名を取得して設定する
+func (r *PayeeRepository) loadAliases(ctx context.Context, userID domain.UserID, payees domain.Payees) error {
+	if len(payees) == 0 {
+		return nil
+	}
+
+	ids := make([]domain.PayeeID, 0, len(payees))
+	for _, payee := range payees {
+		ids = append(ids, payee.ID)
+	}
+
+	runner := getRunner(ctx, r.sess)
+	aliases := make([]*payeeAlias, 0)
+	_, err := runner.Select("payee_id", "name").From(payeeAliasTableName).
+		Where("user_id = ? AND payee_id IN ?", userID, ids).
+		OrderAsc("created_at").
+		OrderAsc("name").
+		LoadContext(ctx, &aliases)
+	if err != nil {
+		return xerrors.Errorf("failed to get payee aliases: %w", err)
+	}
+
+	aliasMap := make(map[domain.PayeeID][]string, len(payees))
+	for _, alias := range aliases {
+		aliasMap[alias.PayeeID] = append(aliasMap[alias.PayeeID], alias.Name)
+	}
+	for _, payee := range payees {
+		payee.Aliases = aliasMap[payee.ID]
+		if payee.Aliases == nil {
+			payee.Aliases = make([]string, 0)
+		}
+	}
+
+	return nil
+}
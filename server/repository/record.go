@@ -12,6 +12,18 @@ import (
 
 const recordTableName = "record"
 
+// recordAmountColumn はRecordの金額（AssetChangeの絶対値）を表すSQL式。振替の場合は移動元・移動先で同額になる
+const recordAmountColumn = "(SELECT MAX(ABS(ac_amount.amount)) FROM asset_change AS ac_amount WHERE ac_amount.record_id = rc.id)"
+
+const (
+	// recordTagNameSubQuery はRecordに指定した名前のTagのいずれかが付与されている場合に行を返す
+	recordTagNameSubQuery = "SELECT 1 FROM record_tag AS rt_cond JOIN tag AS t_cond ON t_cond.id = rt_cond.tag_id WHERE rt_cond.record_id = rc.id AND t_cond.name IN ?"
+	// recordTagNameCountSubQuery はRecordに付与されているTagのうち、指定した名前に一致するTagの種類数を返す
+	recordTagNameCountSubQuery = "SELECT COUNT(DISTINCT t_cond.name) FROM record_tag AS rt_cond JOIN tag AS t_cond ON t_cond.id = rt_cond.tag_id WHERE rt_cond.record_id = rc.id AND t_cond.name IN ?"
+	// recordCategoryDescendantSubQuery は指定した分類とその子孫の分類のIDを返す
+	recordCategoryDescendantSubQuery = "WITH RECURSIVE rcat_cond AS (SELECT id FROM record_category WHERE id IN ? UNION ALL SELECT child.id FROM record_category AS child JOIN rcat_cond ON child.parent_id = rcat_cond.id) SELECT id FROM rcat_cond"
+)
+
 type RecordRepository struct {
 	sess *dbr.Session
 }
@@ -24,7 +36,7 @@ func NewRecordRepository(sess *dbr.Session) *RecordRepository {
 
 func (r *RecordRepository) Insert(ctx context.Context, record *domain.Record) (*domain.Record, error) {
 	runner := getRunner(ctx, r.sess)
-	_, err := runner.InsertInto(recordTableName).Columns("id", "user_id", "record_type", "title", "description", "at").Record(record).Exec()
+	_, err := runner.InsertInto(recordTableName).Columns("id", "user_id", "record_type", "title", "description", "at", "category_id", "payee_id", "tax_rate", "tax_mode", "tax_rounding", "business_ratio").Record(record).Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to insert record: %w", err)
 	}
@@ -45,17 +57,136 @@ func (r *RecordRepository) GetByID(ctx context.Context, userID domain.UserID, id
 	return record, nil
 }
 
+func (r *RecordRepository) GetMultiByIDs(ctx context.Context, userID domain.UserID, ids []domain.RecordID) (domain.Records, error) {
+	runner := getRunner(ctx, r.sess)
+	records := make([]*domain.Record, 0, len(ids))
+
+	if len(ids) == 0 {
+		return records, nil
+	}
+
+	_, err := runner.Select("*").From(recordTableName).Where("user_id = ? AND id IN ?", userID, ids).LoadContext(ctx, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get records by IDs: %w", err)
+	}
+	return records, nil
+}
+
+// GetMultiByAtRange はAtがfrom以上to以下のRecordを古い順に取得する
+func (r *RecordRepository) GetMultiByAtRange(ctx context.Context, userID domain.UserID, from, to time.Time) (domain.Records, error) {
+	runner := getRunner(ctx, r.sess)
+	records := make([]*domain.Record, 0)
+
+	_, err := runner.Select("*").From(recordTableName).
+		Where("user_id = ? AND at >= ? AND at <= ?", userID, from, to).
+		OrderAsc("at").
+		OrderAsc("id").
+		LoadContext(ctx, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get records by at range: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetItemPricesByAtRange はAtがfrom以上to未満の支出から、品名がcharsのいずれかの文字を含む品物の価格を取得する
+// 明細があるRecordは値引きを除いた明細ごとに、明細がないRecordはタイトルと金額を1件の価格として返す
+// 単価は税込で、税抜の単価で登録した明細は消費税を割り振った税込金額を数量で割る（1円未満切り捨て）
+func (r *RecordRepository) GetItemPricesByAtRange(ctx context.Context, userID domain.UserID, from, to time.Time, chars []string) ([]*domain.ItemPrice, error) {
+	runner := getRunner(ctx, r.sess)
+	prices := make([]*domain.ItemPrice, 0)
+
+	recordStmt := runner.Select("rc.id AS record_id", "rc.payee_id", "rc.at", "rc.title AS name", "1 AS quantity", "-ac.amount AS unit_price").
+		From(dbr.I(recordTableName).As("rc")).
+		Join(dbr.I(assetChangeTableName).As("ac"), "ac.record_id = rc.id").
+		Where("rc.user_id = ? AND rc.record_type = ?", userID, domain.RecordTypeExpense).
+		Where("rc.at >= ? AND rc.at < ?", from, to).
+		Where("NOT EXISTS (SELECT 1 FROM " + recordItemTableName + " AS ri_cond WHERE ri_cond.record_id = rc.id)")
+
+	itemStmt := runner.Select("rc.id AS record_id", "rc.payee_id", "rc.at", "ri.name", "ri.quantity", "ri.inclusive_amount DIV ri.quantity AS unit_price").
+		From(dbr.I(recordTableName).As("rc")).
+		Join(dbr.I(recordItemTableName).As("ri"), "ri.record_id = rc.id").
+		Where("rc.user_id = ? AND rc.record_type = ?", userID, domain.RecordTypeExpense).
+		Where("rc.at >= ? AND rc.at < ?", from, to).
+		Where("ri.unit_price > 0")
+
+	if len(chars) > 0 {
+		recordStmt.Where(containsAnyCondition("rc.title", chars))
+		itemStmt.Where(containsAnyCondition("ri.name", chars))
+	}
+
+	_, err := runner.Select("*").From(dbr.UnionAll(recordStmt, itemStmt).As("item_price")).
+		OrderAsc("at").
+		LoadContext(ctx, &prices)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get item prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetMultiTaxableByAtRange はAtがfrom以上to未満の収入・支出のうち、税率または明細があるRecordを取得する
+func (r *RecordRepository) GetMultiTaxableByAtRange(ctx context.Context, userID domain.UserID, from, to time.Time) (domain.Records, error) {
+	runner := getRunner(ctx, r.sess)
+	records := make([]*domain.Record, 0)
+
+	_, err := runner.Select("rc.*").From(dbr.I(recordTableName).As("rc")).
+		Where("rc.user_id = ? AND rc.record_type IN ?", userID, []domain.RecordType{domain.RecordTypeIncome, domain.RecordTypeExpense}).
+		Where("rc.at >= ? AND rc.at < ?", from, to).
+		Where("(rc.tax_rate IS NOT NULL OR EXISTS (SELECT 1 FROM "+recordItemTableName+" AS ri_cond WHERE ri_cond.record_id = rc.id))").
+		OrderAsc("rc.at").
+		LoadContext(ctx, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get taxable records: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetMultiExpenseByAtRange はAtがfrom以上to未満の支出のRecordを取得する
+func (r *RecordRepository) GetMultiExpenseByAtRange(ctx context.Context, userID domain.UserID, from, to time.Time) (domain.Records, error) {
+	runner := getRunner(ctx, r.sess)
+	records := make([]*domain.Record, 0)
+
+	_, err := runner.Select("rc.*").From(dbr.I(recordTableName).As("rc")).
+		Where("rc.user_id = ? AND rc.record_type = ?", userID, domain.RecordTypeExpense).
+		Where("rc.at >= ? AND rc.at < ?", from, to).
+		OrderAsc("rc.at").
+		LoadContext(ctx, &records)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get expense records: %w", err)
+	}
+
+	return records, nil
+}
+
 func (r *RecordRepository) Update(ctx context.Context, record *domain.Record) (*domain.Record, error) {
 	runner := getRunner(ctx, r.sess)
-	_, err := runner.Update(recordTableName).
+	result, err := runner.Update(recordTableName).
+		Set("record_type", record.RecordType).
 		Set("title", record.Title).
 		Set("description", record.Description).
 		Set("at", record.At).
-		Where("id = ? AND user_id = ?", record.ID, record.UserID).
+		Set("category_id", record.CategoryID).
+		Set("payee_id", record.PayeeID).
+		Set("tax_rate", record.TaxRate).
+		Set("tax_mode", record.TaxMode).
+		Set("tax_rounding", record.TaxRounding).
+		Set("business_ratio", record.BusinessRatio).
+		Set("version", incrementVersion).
+		Where("id = ? AND user_id = ? AND version = ?", record.ID, record.UserID, record.Version).
 		Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to update record: %w", err)
 	}
+	resultCount, err := result.RowsAffected()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+	if resultCount == 0 {
+		return nil, xerrors.Errorf("record %s version %d: %w", record.ID, record.Version, domain.ErrConflict)
+	}
+	record.Version++
 
 	return record, nil
 }
@@ -73,37 +204,88 @@ func (r *RecordRepository) Delete(ctx context.Context, userID domain.UserID, id
 	return record, nil
 }
 
-func (r *RecordRepository) GetMultiByUserIDAndAssetID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, assetID *domain.AssetID) ([]*domain.Record, *domain.PageInfo, error) {
+func (r *RecordRepository) DeleteByIDs(ctx context.Context, userID domain.UserID, ids []domain.RecordID) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
 	runner := getRunner(ctx, r.sess)
-	records := make([]*domain.Record, 0)
+	result, err := runner.DeleteFrom(recordTableName).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Exec()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to delete records by IDs: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// DeleteByAssetID は指定したAssetの履歴を持つRecordを削除し、削除した件数を返す
+// 振替の場合は相手のAssetの履歴もあわせて削除される
+func (r *RecordRepository) DeleteByAssetID(ctx context.Context, userID domain.UserID, assetID domain.AssetID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.DeleteFrom(recordTableName).
+		Where("user_id = ? AND id IN (SELECT record_id FROM asset_change WHERE user_id = ? AND asset_id = ?)", userID, userID, assetID).
+		Exec()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to delete records by asset ID: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get affected rows: %w", err)
+	}
 
-	stmt := runner.Select("rc.*").From(dbr.I(recordTableName).As("rc")).Where("rc.user_id = ?", userID)
+	return int(count), nil
+}
+
+func (r *RecordRepository) GetMultiByCondition(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, cond *domain.RecordCondition) ([]*domain.Record, *domain.PageInfo, error) {
+	runner := getRunner(ctx, r.sess)
+	recordWithAmounts := make([]*domain.RecordWithAmount, 0)
 
-	if assetID != nil {
-		stmt.Join(dbr.I(assetChangeTableName).As("ac"), "ac.record_id = rc.id").
-			Where("ac.asset_id = ?", *assetID)
+	sortColumn, err := recordSortColumn(domain.RecordSortKey(pageParam.SortKey))
+	if err != nil {
+		return nil, nil, xerrors.Errorf(": %w", err)
 	}
 
-	stmt, err := paginate(pageParam, stmt)
+	stmt := runner.Select("rc.*", recordAmountColumn+" AS amount").From(dbr.I(recordTableName).As("rc")).Where("rc.user_id = ?", userID)
+	stmt = whereRecordCondition(stmt, cond)
+
+	stmt, err = paginateByColumn(pageParam, stmt, sortColumn, "rc.id")
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to paginate: %w", err)
 	}
 
-	_, err = stmt.LoadContext(ctx, &records)
+	_, err = stmt.LoadContext(ctx, &recordWithAmounts)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to load records: %w", err)
 	}
 
+	records := make([]*domain.Record, 0, len(recordWithAmounts))
+	for _, recordWithAmount := range recordWithAmounts {
+		records = append(records, &recordWithAmount.Record)
+	}
+
 	var startCursor *domain.PageCursor
 	var endCursor *domain.PageCursor
-	if len(records) > 0 {
-		switch pageParam.SortKey {
-		case domain.RecordSortKeyAt.String():
-			startCursor = domain.NewPageCursor(string(records[0].ID), records[0].At.Format("2006-01-02 15:04:05"))
-			endCursor = domain.NewPageCursor(string(records[len(records)-1].ID), records[len(records)-1].At.Format("2006-01-02 15:04:05"))
-		default:
-			panic("unsupported sort key for record")
+	if len(recordWithAmounts) > 0 {
+		first := recordWithAmounts[0]
+		last := recordWithAmounts[len(recordWithAmounts)-1]
+
+		startValue, err := first.CursorValue(domain.RecordSortKey(pageParam.SortKey))
+		if err != nil {
+			return nil, nil, xerrors.Errorf(": %w", err)
 		}
+		endValue, err := last.CursorValue(domain.RecordSortKey(pageParam.SortKey))
+		if err != nil {
+			return nil, nil, xerrors.Errorf(": %w", err)
+		}
+
+		startCursor = domain.NewPageCursor(string(first.ID), startValue)
+		endCursor = domain.NewPageCursor(string(last.ID), endValue)
 	}
 
 	hasNextPage, hasPreviousPage := hasPage(pageParam, len(records))
@@ -118,55 +300,266 @@ func (r *RecordRepository) GetMultiByUserIDAndAssetID(ctx context.Context, pageP
 	return records, pageInfo, nil
 }
 
-func (r *RecordRepository) GetMultiByUserIDAndYearAndMonth(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, year int, month int, tagNames []string, assetIDs []domain.AssetID, recordTypes []domain.RecordType) ([]*domain.Record, *domain.PageInfo, error) {
+func (r *RecordRepository) CountByCondition(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	stmt := runner.Select("COUNT(*)").From(dbr.I(recordTableName).As("rc")).Where("rc.user_id = ?", userID)
+	stmt = whereRecordCondition(stmt, cond)
+
+	var count int
+	err := stmt.LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count records: %w", err)
+	}
+
+	return count, nil
+}
+
+// SumAmountByConditionAndRecordType は条件に一致するRecordのうち、指定した種別のRecordに紐づくAssetChangeの金額を合計する
+func (r *RecordRepository) SumAmountByConditionAndRecordType(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition, recordType domain.RecordType) (int, error) {
 	runner := getRunner(ctx, r.sess)
-	records := make([]*domain.Record, 0)
 
-	stmt := runner.Select("rc.*").From(dbr.I(recordTableName).As("rc")).Where("rc.user_id = ?", userID)
+	recordIDStmt := runner.Select("rc.id").From(dbr.I(recordTableName).As("rc")).
+		Where("rc.user_id = ?", userID).
+		Where("rc.record_type = ?", recordType)
+	recordIDStmt = whereRecordCondition(recordIDStmt, cond)
 
-	if len(tagNames) > 0 {
-		stmt.Join(dbr.I("record_tag").As("rt"), "rt.record_id = rc.id").
-			Join(dbr.I("tag").As("t"), "t.id = rt.tag_id").
-			Where("t.name IN ?", tagNames)
+	var sum int
+	err := runner.Select("COALESCE(SUM(ac.amount), 0)").From(dbr.I(assetChangeTableName).As("ac")).
+		Where("ac.user_id = ?", userID).
+		Where("ac.record_id IN ?", recordIDStmt).
+		LoadOneContext(ctx, &sum)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to sum record amount: %w", err)
 	}
 
-	if len(assetIDs) > 0 {
-		stmt.Join(dbr.I(assetChangeTableName).As("ac"), "ac.record_id = rc.id").
-			Where("ac.asset_id IN ?", assetIDs)
+	return sum, nil
+}
+
+// SumAmountByConditionGroupByCategory は条件に一致する指定した種別のRecordの金額と件数を分類ごとに集計する
+func (r *RecordRepository) SumAmountByConditionGroupByCategory(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition, recordType domain.RecordType) ([]*domain.RecordCategoryAmount, error) {
+	runner := getRunner(ctx, r.sess)
+	amounts := make([]*domain.RecordCategoryAmount, 0)
+
+	// 収入・支出のRecordにはAssetChangeが1件のみのため、JOINしてもRecordは重複しない
+	stmt := runner.Select("rc.category_id", "COALESCE(SUM(ac.amount), 0) AS amount", "COUNT(*) AS record_count").
+		From(dbr.I(recordTableName).As("rc")).
+		Join(dbr.I(assetChangeTableName).As("ac"), "ac.record_id = rc.id").
+		Where("rc.user_id = ?", userID).
+		Where("rc.record_type = ?", recordType)
+	stmt = whereRecordCondition(stmt, cond)
+
+	_, err := stmt.GroupBy("rc.category_id").LoadContext(ctx, &amounts)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sum record amount by category: %w", err)
 	}
 
-	if len(recordTypes) > 0 {
-		stmt.Where("rc.record_type IN ?", recordTypes)
+	return amounts, nil
+}
+
+// SumExpenseByConditionGroupByPayee は条件に一致する支出のRecordの金額と件数を支払先ごとに集計する。金額は正の値で返す
+func (r *RecordRepository) SumExpenseByConditionGroupByPayee(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition) ([]*domain.PayeeAmount, error) {
+	runner := getRunner(ctx, r.sess)
+	amounts := make([]*domain.PayeeAmount, 0)
+
+	stmt := runner.Select("rc.payee_id", "COALESCE(-SUM(ac.amount), 0) AS amount", "COUNT(*) AS record_count").
+		From(dbr.I(recordTableName).As("rc")).
+		Join(dbr.I(assetChangeTableName).As("ac"), "ac.record_id = rc.id").
+		Where("rc.user_id = ?", userID).
+		Where("rc.record_type = ?", domain.RecordTypeExpense)
+	stmt = whereRecordCondition(stmt, cond)
+
+	_, err := stmt.GroupBy("rc.payee_id").OrderDesc("amount").LoadContext(ctx, &amounts)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sum expense by payee: %w", err)
 	}
 
-	stmt.Where(dbr.And(
-		dbr.Gte("rc.at", time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)),
-		dbr.Lt("rc.at", time.Date(year, time.Month(month+1), 1, 0, 0, 0, 0, time.Local)),
-	))
+	return amounts, nil
+}
 
-	stmt, err := paginate(pageParam, stmt)
+// SumAmountByConditionGroupByTag は条件に一致する指定した種別のRecordの金額と件数をTagごとに集計する。金額は正の値で返す
+// 明細があるRecordは明細の税込金額を明細のTagに、明細がないRecordはRecordの金額をRecordのTagに割り当てる
+// 複数のTagが付与されている場合はそれぞれのTagに同じ金額を割り当てるため、Tagごとの金額の合計は総額と一致しない
+func (r *RecordRepository) SumAmountByConditionGroupByTag(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition, recordType domain.RecordType) ([]*domain.TagAmount, error) {
+	runner := getRunner(ctx, r.sess)
+	amounts := make([]*domain.TagAmount, 0)
+
+	recordStmt := runner.Select("rt.tag_id", recordAmountColumn+" AS amount", "rc.id AS record_id").
+		From(dbr.I(recordTableName).As("rc")).
+		LeftJoin(dbr.I(recordTagTableName).As("rt"), "rt.record_id = rc.id").
+		Where("rc.user_id = ?", userID).
+		Where("rc.record_type = ?", recordType).
+		Where("NOT EXISTS (SELECT 1 FROM " + recordItemTableName + " AS ri_cond WHERE ri_cond.record_id = rc.id)")
+	recordStmt = whereRecordCondition(recordStmt, cond)
+
+	itemStmt := runner.Select("rit.tag_id", "ri.inclusive_amount AS amount", "rc.id AS record_id").
+		From(dbr.I(recordTableName).As("rc")).
+		Join(dbr.I(recordItemTableName).As("ri"), "ri.record_id = rc.id").
+		LeftJoin(dbr.I(recordItemTagTableName).As("rit"), "rit.record_item_id = ri.id").
+		Where("rc.user_id = ?", userID).
+		Where("rc.record_type = ?", recordType)
+	itemStmt = whereRecordCondition(itemStmt, cond)
+
+	_, err := runner.Select("tag_id", "COALESCE(SUM(amount), 0) AS amount", "COUNT(DISTINCT record_id) AS record_count").
+		From(dbr.UnionAll(recordStmt, itemStmt).As("tag_amount")).
+		GroupBy("tag_id").
+		OrderDesc("amount").
+		LoadContext(ctx, &amounts)
 	if err != nil {
-		return nil, nil, xerrors.Errorf("failed to paginate: %w", err)
+		return nil, xerrors.Errorf("failed to sum record amount by tag: %w", err)
+	}
+
+	return amounts, nil
+}
+
+// UpdateCategoryIDByTagID は指定したTagが付与された未分類のRecordに分類を設定し、設定した件数を返す
+func (r *RecordRepository) UpdateCategoryIDByTagID(ctx context.Context, userID domain.UserID, tagID domain.TagID, recordType domain.RecordType, categoryID domain.RecordCategoryID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.Update(recordTableName).
+		Set("category_id", categoryID).
+		Set("version", incrementVersion).
+		Where("user_id = ? AND record_type = ? AND category_id IS NULL", userID, recordType).
+		Where("EXISTS (SELECT 1 FROM record_tag AS rt_cond WHERE rt_cond.record_id = record.id AND rt_cond.tag_id = ?)", tagID).
+		Exec()
+	if err != nil {
+		return 0, xerrors.Errorf("failed to update record category by tag: %w", err)
 	}
 
-	_, err = stmt.LoadContext(ctx, &records)
+	count, err := result.RowsAffected()
 	if err != nil {
-		return nil, nil, xerrors.Errorf("failed to load records: %w", err)
+		return 0, xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// whereRecordCondition はrcをエイリアスとしたrecordテーブルのSELECTに絞り込み条件を追加する
+func whereRecordCondition(stmt *dbr.SelectStmt, cond *domain.RecordCondition) *dbr.SelectStmt {
+	if cond == nil {
+		return stmt
+	}
+
+	// JOINするとRecordが重複してページネーションが崩れるため、関連テーブルの条件はすべてサブクエリで絞り込む
+	for _, tagCond := range cond.Tags {
+		if len(tagCond.Names) == 0 {
+			continue
+		}
+
+		switch tagCond.Mode {
+		case domain.TagMatchModeAny:
+			stmt.Where("EXISTS ("+recordTagNameSubQuery+")", tagCond.Names)
+		case domain.TagMatchModeAll:
+			stmt.Where("("+recordTagNameCountSubQuery+") = ?", tagCond.Names, len(tagCond.UniqueNames()))
+		case domain.TagMatchModeNone:
+			stmt.Where("NOT EXISTS ("+recordTagNameSubQuery+")", tagCond.Names)
+		}
+	}
+
+	if len(cond.AssetIDs) > 0 {
+		stmt.Where("EXISTS (SELECT 1 FROM asset_change AS ac_cond WHERE ac_cond.record_id = rc.id AND ac_cond.asset_id IN ?)", cond.AssetIDs)
+	}
+
+	if len(cond.CategoryIDs) > 0 {
+		stmt.Where("rc.category_id IN ("+recordCategoryDescendantSubQuery+")", cond.CategoryIDs)
+	}
+
+	if len(cond.PayeeIDs) > 0 {
+		stmt.Where("rc.payee_id IN ?", cond.PayeeIDs)
+	}
+
+	if len(cond.RecordTypes) > 0 {
+		stmt.Where("rc.record_type IN ?", cond.RecordTypes)
+	}
+
+	if cond.AtFrom != nil {
+		stmt.Where(dbr.Gte("rc.at", *cond.AtFrom))
+	}
+	if cond.AtTo != nil {
+		stmt.Where(dbr.Lt("rc.at", *cond.AtTo))
+	}
+
+	if cond.AmountMin != nil {
+		stmt.Where(recordAmountColumn+" >= ?", *cond.AmountMin)
+	}
+	if cond.AmountMax != nil {
+		stmt.Where(recordAmountColumn+" <= ?", *cond.AmountMax)
+	}
+
+	if cond.Text != "" {
+		pattern := "%" + escapeLike(cond.Text) + "%"
+		stmt.Where("(rc.title LIKE ? OR rc.description LIKE ?)", pattern, pattern)
+	}
+
+	return stmt
+}
+
+func recordSortColumn(sortKey domain.RecordSortKey) (string, error) {
+	switch sortKey {
+	case domain.RecordSortKeyAt:
+		return "rc.at", nil
+	case domain.RecordSortKeyCreatedAt:
+		return "rc.created_at", nil
+	case domain.RecordSortKeyTitle:
+		return "rc.title", nil
+	case domain.RecordSortKeyAmount:
+		return recordAmountColumn, nil
+	default:
+		return "", xerrors.Errorf("unsupported sort key for record %s: %w", sortKey, domain.ErrInvalidPageParam)
+	}
+}
+
+// recordSearchScoreKeyColumn はRecordのタイトル・説明、付与されたTagの名前、関連するAssetの名前の全文検索スコアを合計するSQL式
+// カーソルの比較で誤差が出ないように、domain.SearchScoreScale倍して整数に丸める
+const recordSearchScoreKeyColumn = "CAST(ROUND((" +
+	"MATCH(rc.title, rc.description) AGAINST(? IN NATURAL LANGUAGE MODE)" +
+	" + COALESCE((SELECT MAX(MATCH(t_search.name) AGAINST(? IN NATURAL LANGUAGE MODE)) FROM record_tag AS rt_search JOIN tag AS t_search ON t_search.id = rt_search.tag_id WHERE rt_search.record_id = rc.id), 0)" +
+	" + COALESCE((SELECT MAX(MATCH(a_search.name) AGAINST(? IN NATURAL LANGUAGE MODE)) FROM asset_change AS ac_search JOIN asset AS a_search ON a_search.id = ac_search.asset_id WHERE ac_search.record_id = rc.id), 0)" +
+	") * ?) AS SIGNED)"
+
+// recordSearchMatchCondition は全文検索に一致するRecordに絞り込むWHERE句。MATCHをWHERE句に置いてFULLTEXTインデックスを使わせる
+const recordSearchMatchCondition = "(MATCH(rc.title, rc.description) AGAINST(? IN NATURAL LANGUAGE MODE)" +
+	" OR EXISTS (SELECT 1 FROM record_tag AS rt_match JOIN tag AS t_match ON t_match.id = rt_match.tag_id WHERE rt_match.record_id = rc.id AND MATCH(t_match.name) AGAINST(? IN NATURAL LANGUAGE MODE))" +
+	" OR EXISTS (SELECT 1 FROM asset_change AS ac_match JOIN asset AS a_match ON a_match.id = ac_match.asset_id WHERE ac_match.record_id = rc.id AND MATCH(a_match.name) AGAINST(? IN NATURAL LANGUAGE MODE)))"
+
+// Search は全文検索に一致するRecordをスコアの高い順に取得する
+// スコアの降順でのみ並べるため、ページネーションはfirst/afterのみ対応する
+func (r *RecordRepository) Search(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, query string, cond *domain.RecordCondition) ([]*domain.RecordWithScore, *domain.PageInfo, error) {
+	runner := getRunner(ctx, r.sess)
+	recordWithScores := make([]*domain.RecordWithScore, 0)
+
+	if pageParam.First == nil {
+		return nil, nil, xerrors.Errorf("search supports only first and after: %w", domain.ErrInvalidPageParam)
+	}
+
+	stmt := runner.Select("s.*").From(recordSearchStmt(runner, userID, query, cond).As("s")).
+		OrderDesc("s.score_key").
+		OrderAsc("s.id").
+		Limit(uint64(*pageParam.First))
+
+	if pageParam.After != nil {
+		scoreKey, err := domain.ParseSearchScoreCursor(pageParam.After.Value)
+		if err != nil {
+			return nil, nil, xerrors.Errorf(": %w", err)
+		}
+		stmt.Where("(s.score_key < ? OR (s.score_key = ? AND s.id > ?))", scoreKey, scoreKey, pageParam.After.ID)
+	}
+
+	_, err := stmt.LoadContext(ctx, &recordWithScores)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to search records: %w", err)
 	}
 
 	var startCursor *domain.PageCursor
 	var endCursor *domain.PageCursor
-	if len(records) > 0 {
-		switch pageParam.SortKey {
-		case domain.RecordSortKeyAt.String():
-			startCursor = domain.NewPageCursor(string(records[0].ID), records[0].At.Format("2006-01-02 15:04:05"))
-			endCursor = domain.NewPageCursor(string(records[len(records)-1].ID), records[len(records)-1].At.Format("2006-01-02 15:04:05"))
-		default:
-			panic("unsupported sort key for record")
-		}
+	if len(recordWithScores) > 0 {
+		first := recordWithScores[0]
+		last := recordWithScores[len(recordWithScores)-1]
+
+		startCursor = domain.NewPageCursor(string(first.ID), first.CursorValue())
+		endCursor = domain.NewPageCursor(string(last.ID), last.CursorValue())
 	}
 
-	hasNextPage, hasPreviousPage := hasPage(pageParam, len(records))
+	hasNextPage, hasPreviousPage := hasPage(pageParam, len(recordWithScores))
 
 	pageInfo := &domain.PageInfo{
 		StartCursor:     startCursor,
@@ -175,5 +568,29 @@ func (r *RecordRepository) GetMultiByUserIDAndYearAndMonth(ctx context.Context,
 		HasPreviousPage: hasPreviousPage,
 	}
 
-	return records, pageInfo, nil
+	return recordWithScores, pageInfo, nil
+}
+
+func (r *RecordRepository) CountSearch(ctx context.Context, userID domain.UserID, query string, cond *domain.RecordCondition) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var count int
+	err := runner.Select("COUNT(*)").From(recordSearchStmt(runner, userID, query, cond).As("s")).
+		LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count searched records: %w", err)
+	}
+
+	return count, nil
+}
+
+// recordSearchStmt は絞り込み条件と全文検索に一致するRecordとスコアを返すSELECTを作成する
+// スコアをカーソルで扱えるように、呼び出し側で派生テーブルとして使う
+func recordSearchStmt(runner dbr.SessionRunner, userID domain.UserID, query string, cond *domain.RecordCondition) *dbr.SelectStmt {
+	stmt := runner.Select("rc.*", dbr.Expr(recordSearchScoreKeyColumn+" AS score_key", query, query, query, domain.SearchScoreScale)).
+		From(dbr.I(recordTableName).As("rc")).
+		Where("rc.user_id = ?", userID).
+		Where(recordSearchMatchCondition, query, query, query)
+
+	return whereRecordCondition(stmt, cond)
 }
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const recordCategoryTableName = "record_category"
+
+type RecordCategoryRepository struct {
+	sess *dbr.Session
+}
+
+func NewRecordCategoryRepository(sess *dbr.Session) *RecordCategoryRepository {
+	return &RecordCategoryRepository{
+		sess: sess,
+	}
+}
+
+func (r *RecordCategoryRepository) Insert(ctx context.Context, category *domain.RecordCategory) (*domain.RecordCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertInto(recordCategoryTableName).
+		Columns("id", "user_id", "record_type", "name", "parent_id", "expense_account").
+		Record(category).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to insert record category: %w", err)
+	}
+
+	return category, nil
+}
+
+func (r *RecordCategoryRepository) Update(ctx context.Context, category *domain.RecordCategory) (*domain.RecordCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(recordCategoryTableName).
+		Set("name", category.Name).
+		Set("parent_id", category.ParentID).
+		Set("expense_account", category.ExpenseAccount).
+		Where("id = ? AND user_id = ?", category.ID, category.UserID).
+		Exec()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to update record category: %w", err)
+	}
+
+	return category, nil
+}
+
+func (r *RecordCategoryRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID) (*domain.RecordCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	category := &domain.RecordCategory{}
+
+	err := runner.Select("*").From(recordCategoryTableName).Where("id = ? AND user_id = ?", id, userID).LoadOneContext(ctx, category)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get record category by ID: %w", err)
+	}
+
+	return category, nil
+}
+
+func (r *RecordCategoryRepository) GetMultiByIDs(ctx context.Context, userID domain.UserID, ids []domain.RecordCategoryID) (domain.RecordCategories, error) {
+	runner := getRunner(ctx, r.sess)
+	categories := make([]*domain.RecordCategory, 0, len(ids))
+
+	if len(ids) == 0 {
+		return categories, nil
+	}
+
+	_, err := runner.Select("*").From(recordCategoryTableName).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		LoadContext(ctx, &categories)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record categories by IDs: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetMultiByUserIDAndRecordType はユーザーの分類を名前順に取得する。recordTypeがnilの場合は収入・支出の両方を取得する
+func (r *RecordCategoryRepository) GetMultiByUserIDAndRecordType(ctx context.Context, userID domain.UserID, recordType *domain.RecordType) (domain.RecordCategories, error) {
+	runner := getRunner(ctx, r.sess)
+	categories := make([]*domain.RecordCategory, 0)
+
+	stmt := runner.Select("*").From(recordCategoryTableName).Where("user_id = ?", userID)
+	if recordType != nil {
+		stmt.Where("record_type = ?", *recordType)
+	}
+
+	_, err := stmt.OrderAsc("name").OrderAsc("id").LoadContext(ctx, &categories)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record categories by user ID: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *RecordCategoryRepository) GetMultiByParentIDs(ctx context.Context, userID domain.UserID, parentIDs []domain.RecordCategoryID) (domain.RecordCategories, error) {
+	runner := getRunner(ctx, r.sess)
+	categories := make([]*domain.RecordCategory, 0)
+
+	if len(parentIDs) == 0 {
+		return categories, nil
+	}
+
+	_, err := runner.Select("*").From(recordCategoryTableName).
+		Where("user_id = ? AND parent_id IN ?", userID, parentIDs).
+		OrderAsc("name").
+		OrderAsc("id").
+		LoadContext(ctx, &categories)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record categories by parent IDs: %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetByRecordTypeAndParentIDAndName は同じ親の下にある同名の分類を取得する
+func (r *RecordCategoryRepository) GetByRecordTypeAndParentIDAndName(ctx context.Context, userID domain.UserID, recordType domain.RecordType, parentID *domain.RecordCategoryID, name string) (*domain.RecordCategory, error) {
+	runner := getRunner(ctx, r.sess)
+	category := &domain.RecordCategory{}
+
+	stmt := runner.Select("*").From(recordCategoryTableName).
+		Where("user_id = ? AND record_type = ? AND name = ?", userID, recordType, name)
+	if parentID == nil {
+		stmt.Where("parent_id IS NULL")
+	} else {
+		stmt.Where("parent_id = ?", *parentID)
+	}
+
+	err := stmt.Limit(1).LoadOneContext(ctx, category)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get record category by name: %w", err)
+	}
+
+	return category, nil
+}
+
+// UpdateParentIDByParentID は親の分類を付け替える。分類を削除する前に子の分類を削除対象の親へ移動するために使う
+func (r *RecordCategoryRepository) UpdateParentIDByParentID(ctx context.Context, userID domain.UserID, parentID domain.RecordCategoryID, newParentID *domain.RecordCategoryID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(recordCategoryTableName).
+		Set("parent_id", newParentID).
+		Where("user_id = ? AND parent_id = ?", userID, parentID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to update parent of record categories: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecordCategoryRepository) Delete(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID) (domain.RecordCategoryID, error) {
+	runner := getRunner(ctx, r.sess)
+	result, err := runner.DeleteFrom(recordCategoryTableName).
+		Where("id = ? AND user_id = ?", id, userID).
+		Exec()
+	if err != nil {
+		return "", xerrors.Errorf("failed to delete record category: %w", err)
+	}
+
+	resultCount, err := result.RowsAffected()
+	if err != nil {
+		return "", xerrors.Errorf("failed to get affected rows: %w", err)
+	}
+	if resultCount == 0 {
+		return "", domain.ErrEntityNotFound
+	}
+
+	return id, nil
+}
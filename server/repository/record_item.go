@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+
+	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	recordItemTableName    = "record_item"
+	recordItemTagTableName = "record_item_tag"
+)
+
+type RecordItemRepository struct {
+	sess *dbr.Session
+}
+
+func NewRecordItemRepository(sess *dbr.Session) *RecordItemRepository {
+	return &RecordItemRepository{
+		sess: sess,
+	}
+}
+
+// recordItemTag は明細に付与されたTag
+type recordItemTag struct {
+	domain.Tag
+	RecordItemID domain.RecordItemID
+}
+
+// InsertMulti は明細と明細に付与されたTagを保存する
+func (r *RecordItemRepository) InsertMulti(ctx context.Context, items domain.RecordItems) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	stmt := runner.InsertInto(recordItemTableName).
+		Columns("id", "user_id", "record_id", "name", "quantity", "unit_price", "tax_rate", "inclusive_amount", "sort_order")
+	for _, item := range items {
+		stmt = stmt.Record(item)
+	}
+	_, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to insert record items: %w", err)
+	}
+
+	tagStmt := runner.InsertInto(recordItemTagTableName).Columns("record_item_id", "tag_id")
+	hasTags := false
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			tagStmt = tagStmt.Values(item.ID, tag.ID)
+			hasTags = true
+		}
+	}
+	if !hasTags {
+		return nil
+	}
+	_, err = tagStmt.ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to insert record item tags: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByRecordID はRecordの明細をすべて削除する。明細に付与されたTagは外部キーで削除される
+func (r *RecordItemRepository) DeleteByRecordID(ctx context.Context, userID domain.UserID, recordID domain.RecordID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(recordItemTableName).
+		Where("user_id = ? AND record_id = ?", userID, recordID).
+		ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to delete record items: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateInclusiveAmounts は明細の税込金額を更新する
+func (r *RecordItemRepository) UpdateInclusiveAmounts(ctx context.Context, items domain.RecordItems) error {
+	runner := getRunner(ctx, r.sess)
+	for _, item := range items {
+		_, err := runner.Update(recordItemTableName).
+			Set("inclusive_amount", item.InclusiveAmount).
+			Where("user_id = ? AND id = ?", item.UserID, item.ID).
+			ExecContext(ctx)
+		if err != nil {
+			return xerrors.Errorf("failed to update record item inclusive amount: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetMultiByRecordIDs は明細をRecordごとの表示順で取得する
+func (r *RecordItemRepository) GetMultiByRecordIDs(ctx context.Context, userID domain.UserID, recordIDs []domain.RecordID) (domain.RecordItems, error) {
+	runner := getRunner(ctx, r.sess)
+	items := make(domain.RecordItems, 0)
+
+	if len(recordIDs) == 0 {
+		return items, nil
+	}
+
+	_, err := runner.Select("*").From(recordItemTableName).
+		Where("user_id = ? AND record_id IN ?", userID, recordIDs).
+		OrderAsc("record_id").
+		OrderAsc("sort_order").
+		LoadContext(ctx, &items)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record items by record IDs: %w", err)
+	}
+
+	err = r.loadTags(ctx, userID, items)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return items, nil
+}
+
+// loadTags は明細に付与されたTagを取得して設定する
+func (r *RecordItemRepository) loadTags(ctx context.Context, userID domain.UserID, items domain.RecordItems) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]domain.RecordItemID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	runner := getRunner(ctx, r.sess)
+	tags := make([]*recordItemTag, 0)
+	_, err := runner.Select("rit.record_item_id, t.*").From(dbr.I(recordItemTagTableName).As("rit")).
+		Join(dbr.I(tagtableName).As("t"), "t.id = rit.tag_id").
+		Where(dbr.Eq("t.user_id", userID)).
+		Where("rit.record_item_id IN ?", ids).
+		OrderAsc("t.name").
+		LoadContext(ctx, &tags)
+	if err != nil {
+		return xerrors.Errorf("failed to load record item tags: %w", err)
+	}
+
+	tagMap := make(map[domain.RecordItemID][]*domain.Tag, len(items))
+	for _, tag := range tags {
+		tagMap[tag.RecordItemID] = append(tagMap[tag.RecordItemID], &tag.Tag)
+	}
+	for _, item := range items {
+		item.Tags = tagMap[item.ID]
+		if item.Tags == nil {
+			item.Tags = make([]*domain.Tag, 0)
+		}
+	}
+
+	return nil
+}
+
+// MoveTagIDs はsourceTagIDsのTagが付与された明細にtargetTagIDのTagを付与する。sourceTagIDsとの紐づけは残る
+func (r *RecordItemRepository) MoveTagIDs(ctx context.Context, sourceTagIDs []domain.TagID, targetTagID domain.TagID) error {
+	if len(sourceTagIDs) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertBySql(
+		"INSERT IGNORE INTO "+recordItemTagTableName+" (record_item_id, tag_id) SELECT DISTINCT record_item_id, ? FROM "+recordItemTagTableName+" WHERE tag_id IN ?",
+		targetTagID, sourceTagIDs,
+	).ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to move record_item_tag: %w", err)
+	}
+
+	return nil
+}
@@ -44,3 +44,60 @@ func (r *RecordTagRepository) DeleteByRecordID(ctx context.Context, recordID dom
 
 	return nil
 }
+
+// MoveTagIDs はsourceTagIDsのTagが付与されたRecordにtargetTagIDのTagを付与する。すでに付与されている場合は重複させない
+// sourceTagIDsとの紐づけは残るため、呼び出し側でTagを削除すること
+func (r *RecordTagRepository) MoveTagIDs(ctx context.Context, sourceTagIDs []domain.TagID, targetTagID domain.TagID) error {
+	if len(sourceTagIDs) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.InsertBySql(
+		"INSERT IGNORE INTO "+recordTagTableName+" (record_id, tag_id) SELECT DISTINCT record_id, ? FROM "+recordTagTableName+" WHERE tag_id IN ?",
+		targetTagID, sourceTagIDs,
+	).ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to move record_tag: %w", err)
+	}
+
+	return nil
+}
+
+// InsertMulti はすべてのRecordにすべてのTagを付与する。すでに付与されている組み合わせは無視する
+func (r *RecordTagRepository) InsertMulti(ctx context.Context, recordIDs []domain.RecordID, tagIDs []domain.TagID) error {
+	if len(recordIDs) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	stmt := runner.InsertInto(recordTagTableName).Ignore().Columns("record_id", "tag_id")
+	for _, recordID := range recordIDs {
+		for _, tagID := range tagIDs {
+			stmt.Values(recordID, tagID)
+		}
+	}
+
+	_, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to insert record_tags: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecordTagRepository) DeleteByRecordIDsAndTagIDs(ctx context.Context, recordIDs []domain.RecordID, tagIDs []domain.TagID) error {
+	if len(recordIDs) == 0 || len(tagIDs) == 0 {
+		return nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(recordTagTableName).
+		Where("record_id IN ? AND tag_id IN ?", recordIDs, tagIDs).
+		ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to delete record_tags by record IDs and tag IDs: %w", err)
+	}
+
+	return nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"kakeibo-web-server/domain"
+	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr/v2"
+	"golang.org/x/xerrors"
 )
 
 type TxKey struct{}
@@ -17,8 +22,14 @@ type Repository struct {
 	Tag                 *TagRepository
 	RecordTag           *RecordTagRepository
 	Record              *RecordRepository
+	RecordCategory      *RecordCategoryRepository
 	AssetChange         *AssetChangeRepository
 	TotalAssetsSnapshot *TotalAssetsSnapshotRepository
+	IdempotencyKey      *IdempotencyKeyRepository
+	Payee               *PayeeRepository
+	Attachment          *AttachmentRepository
+	RecordItem          *RecordItemRepository
+	Goal                *GoalRepository
 }
 
 func NewRepository(sess *dbr.Session) *Repository {
@@ -30,8 +41,14 @@ func NewRepository(sess *dbr.Session) *Repository {
 		Tag:                 NewTagRepository(sess),
 		RecordTag:           NewRecordTagRepository(sess),
 		Record:              NewRecordRepository(sess),
+		RecordCategory:      NewRecordCategoryRepository(sess),
 		AssetChange:         NewAssetChangeRepository(sess),
 		TotalAssetsSnapshot: NewTotalAssetsSnapshotRepository(sess),
+		IdempotencyKey:      NewIdempotencyKeyRepository(sess),
+		Payee:               NewPayeeRepository(sess),
+		Attachment:          NewAttachmentRepository(sess),
+		RecordItem:          NewRecordItemRepository(sess),
+		Goal:                NewGoalRepository(sess),
 	}
 }
 
@@ -67,11 +84,56 @@ func getRunner(ctx context.Context, sess *dbr.Session) dbr.SessionRunner {
 	return tx
 }
 
+// shiftSortOrderExcludingIDs はexcludeIDs以外の行の並び順を、現在の順（同じ場合はID順）を保ったままoffset+1から振り直す
+// 並び替えで1からoffsetまでを振り直したときに、指定されなかった行と並び順が重ならないようにする
+func shiftSortOrderExcludingIDs[T ~string](ctx context.Context, runner dbr.SessionRunner, tableName string, userID domain.UserID, excludeIDs []T, offset int) error {
+	condition := "user_id = ?"
+	args := []any{userID}
+	if len(excludeIDs) > 0 {
+		condition += " AND id NOT IN ?"
+		args = append(args, excludeIDs)
+	}
+	args = append(args, offset)
+
+	_, err := runner.UpdateBySql(
+		"UPDATE "+tableName+" AS t"+
+			" JOIN (SELECT id, ROW_NUMBER() OVER (ORDER BY sort_order, id) AS position FROM "+tableName+" WHERE "+condition+") AS o ON o.id = t.id"+
+			" SET t.sort_order = ? + o.position, t.version = t.version + 1",
+		args...,
+	).ExecContext(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to shift sort order of %s: %w", tableName, err)
+	}
+
+	return nil
+}
+
+// mysqlErrDuplicateEntry は一意制約に違反したときのMySQLのエラー番号
+const mysqlErrDuplicateEntry = 1062
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// incrementVersion は楽観的排他制御のバージョンを1増やす。行を更新するときは必ずあわせて設定する
+var incrementVersion = dbr.Expr("version + 1")
+
 func paginate(pageParam *domain.PageParam, stmt *dbr.SelectStmt) (*dbr.SelectStmt, error) {
 	if pageParam == nil {
 		return stmt, nil
 	}
 
+	return paginateByColumn(pageParam, stmt, pageParam.SortKey, "id")
+}
+
+// paginateByColumn はソートキーに対応するカラム（SQL式も可）とIDのカラムを指定してページネーションを行う
+// JOINでカラム名が曖昧になる場合や、集計値でソートする場合に使用する
+func paginateByColumn(pageParam *domain.PageParam, stmt *dbr.SelectStmt, sortColumn string, idColumn string) (*dbr.SelectStmt, error) {
+	if pageParam == nil {
+		return stmt, nil
+	}
+
 	if pageParam.First != nil && pageParam.Last != nil {
 		return nil, domain.ErrInvalidPageParam
 	}
@@ -79,13 +141,8 @@ func paginate(pageParam *domain.PageParam, stmt *dbr.SelectStmt) (*dbr.SelectStm
 		stmt.Limit(uint64(*pageParam.First))
 		if pageParam.After != nil {
 			stmt.Where(
-				dbr.Or(
-					dbr.Gt(pageParam.SortKey, pageParam.After.Value),
-					dbr.And(
-						dbr.Eq(pageParam.SortKey, pageParam.After.Value),
-						dbr.Gt("id", pageParam.After.ID),
-					),
-				),
+				fmt.Sprintf("(%s > ? OR (%s = ? AND %s > ?))", sortColumn, sortColumn, idColumn),
+				pageParam.After.Value, pageParam.After.Value, pageParam.After.ID,
 			)
 		}
 	}
@@ -94,28 +151,37 @@ func paginate(pageParam *domain.PageParam, stmt *dbr.SelectStmt) (*dbr.SelectStm
 		stmt.Limit(uint64(*pageParam.Last))
 		if pageParam.Before != nil {
 			stmt.Where(
-				dbr.Or(
-					dbr.Lt(pageParam.SortKey, pageParam.Before.Value),
-					dbr.And(
-						dbr.Eq(pageParam.SortKey, pageParam.Before.Value),
-						dbr.Lt("id", pageParam.Before.ID),
-					),
-				),
+				fmt.Sprintf("(%s < ? OR (%s = ? AND %s < ?))", sortColumn, sortColumn, idColumn),
+				pageParam.Before.Value, pageParam.Before.Value, pageParam.Before.ID,
 			)
 		}
 	}
 
 	if pageParam.IsReverse() {
-		stmt = stmt.OrderDesc(pageParam.SortKey)
-		stmt = stmt.OrderDesc("id")
+		stmt = stmt.OrderDesc(sortColumn)
+		stmt = stmt.OrderDesc(idColumn)
 	} else {
-		stmt = stmt.OrderAsc(pageParam.SortKey)
-		stmt = stmt.OrderAsc("id")
+		stmt = stmt.OrderAsc(sortColumn)
+		stmt = stmt.OrderAsc(idColumn)
 	}
 
 	return stmt, nil
 }
 
+// escapeLike はLIKE句のパターンとして使う文字列の特殊文字をエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// containsAnyCondition はcolumnがwordsのいずれかを含む条件を返す
+func containsAnyCondition(column string, words []string) dbr.Builder {
+	conds := make([]dbr.Builder, 0, len(words))
+	for _, word := range words {
+		conds = append(conds, dbr.Expr(column+" LIKE ?", "%"+escapeLike(word)+"%"))
+	}
+	return dbr.Or(conds...)
+}
+
 func hasPage(pageParam *domain.PageParam, resultCount int) (hasNextPage, hasPreviousPage bool) {
 	hasNextPage = false
 	hasPreviousPage = false
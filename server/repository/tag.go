@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"kakeibo-web-server/domain"
+	"strconv"
 
 	"github.com/gocraft/dbr/v2"
 	"golang.org/x/xerrors"
@@ -23,11 +25,14 @@ func NewTagRepository(sess *dbr.Session) *TagRepository {
 func (r *TagRepository) Insert(cxt context.Context, tag *domain.Tag) (*domain.Tag, error) {
 	runner := getRunner(cxt, r.sess)
 	_, err := runner.InsertInto(tagtableName).
-		Columns("id", "user_id", "name").
+		Columns("id", "user_id", "name", "color", "icon", "archived", "sort_order", "business_ratio").
 		Record(tag).
 		Exec()
 
 	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, xerrors.Errorf("tag %s: %w", tag.Name, domain.ErrTagNameConflict)
+		}
 		return nil, xerrors.Errorf("failed to insert tag: %w", err)
 	}
 
@@ -38,9 +43,17 @@ func (r *TagRepository) Update(ctx context.Context, tag *domain.Tag) (*domain.Ta
 	runner := getRunner(ctx, r.sess)
 	result, err := runner.Update(tagtableName).
 		Set("name", tag.Name).
-		Where("id = ? AND user_id = ?", tag.ID, tag.UserID).
+		Set("color", tag.Color).
+		Set("icon", tag.Icon).
+		Set("archived", tag.Archived).
+		Set("business_ratio", tag.BusinessRatio).
+		Set("version", incrementVersion).
+		Where("id = ? AND user_id = ? AND version = ?", tag.ID, tag.UserID, tag.Version).
 		Exec()
 	if err != nil {
+		if isDuplicateEntry(err) {
+			return nil, xerrors.Errorf("tag %s: %w", tag.Name, domain.ErrTagNameConflict)
+		}
 		return nil, xerrors.Errorf("failed to update tag: %w", err)
 	}
 	resultCount, err := result.RowsAffected()
@@ -48,17 +61,45 @@ func (r *TagRepository) Update(ctx context.Context, tag *domain.Tag) (*domain.Ta
 		return nil, xerrors.Errorf("failed to get affected rows: %w", err)
 	}
 	if resultCount == 0 {
-		return nil, domain.ErrEntityNotFound
+		return nil, xerrors.Errorf("tag %s version %d: %w", tag.ID, tag.Version, domain.ErrConflict)
+	}
+	tag.Version++
+
+	return tag, nil
+}
+
+func (r *TagRepository) GetByID(ctx context.Context, userID domain.UserID, id domain.TagID) (*domain.Tag, error) {
+	runner := getRunner(ctx, r.sess)
+	tag := &domain.Tag{}
+
+	err := runner.Select("*").From(tagtableName).
+		Where(dbr.Eq("id", id)).
+		Where(dbr.Eq("user_id", userID)).
+		LoadOneContext(ctx, tag)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, domain.ErrEntityNotFound
+		}
+		return nil, xerrors.Errorf("failed to get tag by ID: %w", err)
 	}
 
 	return tag, nil
 }
 
-func (r *TagRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID) ([]*domain.Tag, *domain.PageInfo, error) {
+// GetMultiByUserID はユーザーのTagを取得する。includeArchivedがfalseの場合はアーカイブしたTagを除く
+func (r *TagRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, includeArchived bool) ([]*domain.Tag, *domain.PageInfo, error) {
 	runner := getRunner(ctx, r.sess)
 	stmt := runner.Select("*").From(tagtableName).Where(dbr.Eq("user_id", userID))
+	if !includeArchived {
+		stmt.Where(dbr.Eq("archived", false))
+	}
+
+	sortColumn, err := tagSortColumn(domain.TagSortKey(pageParam.SortKey))
+	if err != nil {
+		return nil, nil, xerrors.Errorf(": %w", err)
+	}
 
-	stmt, err := paginate(pageParam, stmt)
+	stmt, err = paginateByColumn(pageParam, stmt, sortColumn, "id")
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to paginate tags: %w", err)
 	}
@@ -72,11 +113,15 @@ func (r *TagRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.
 	var startCursor *domain.PageCursor
 	var endCursor *domain.PageCursor
 	if len(tags) > 0 {
-		if pageParam.SortKey == domain.TagSortKeyName.String() {
+		switch pageParam.SortKey {
+		case domain.TagSortKeyName.String():
 			startCursor = domain.NewPageCursor(string(tags[0].ID), tags[0].Name)
 			endCursor = domain.NewPageCursor(string(tags[len(tags)-1].ID), tags[len(tags)-1].Name)
-		} else {
-			panic("unsupported sort key for asset repository")
+		case domain.TagSortKeyManual.String():
+			startCursor = domain.NewPageCursor(string(tags[0].ID), strconv.Itoa(tags[0].SortOrder))
+			endCursor = domain.NewPageCursor(string(tags[len(tags)-1].ID), strconv.Itoa(tags[len(tags)-1].SortOrder))
+		default:
+			panic("unsupported sort key for tag repository")
 		}
 	}
 
@@ -92,6 +137,23 @@ func (r *TagRepository) GetMultiByUserID(ctx context.Context, pageParam *domain.
 	return tags, pageInfo, nil
 }
 
+func (r *TagRepository) CountByUserID(ctx context.Context, userID domain.UserID, includeArchived bool) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	stmt := runner.Select("COUNT(*)").From(tagtableName).Where(dbr.Eq("user_id", userID))
+	if !includeArchived {
+		stmt.Where(dbr.Eq("archived", false))
+	}
+
+	var count int
+	err := stmt.LoadOneContext(ctx, &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count tags: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *TagRepository) Delete(ctx context.Context, userID domain.UserID, tagID domain.TagID) (domain.TagID, error) {
 	runner := getRunner(ctx, r.sess)
 
@@ -135,6 +197,26 @@ func (r *TagRepository) GetMultiByNames(ctx context.Context, userID domain.UserI
 	return tags, nil
 }
 
+func (r *TagRepository) GetMultiByIDs(ctx context.Context, userID domain.UserID, tagIDs []domain.TagID) (domain.Tags, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	tags := make([]*domain.Tag, 0, len(tagIDs))
+
+	_, err := runner.Select("*").From(tagtableName).
+		Where(dbr.Eq("user_id", userID)).
+		Where("id IN ?", tagIDs).
+		LoadContext(ctx, &tags)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load tags by IDs: %w", err)
+	}
+
+	return tags, nil
+}
+
 func (r *TagRepository) GetMultiWithRecordIDByRecordIDs(ctx context.Context, userID domain.UserID, recordIDs []domain.RecordID) ([]*domain.TagWithRecordID, error) {
 	if len(recordIDs) == 0 {
 		return nil, nil
@@ -155,3 +237,143 @@ func (r *TagRepository) GetMultiWithRecordIDByRecordIDs(ctx context.Context, use
 
 	return tagWithRecordIDs, nil
 }
+
+func (r *TagRepository) GetStatsByTagIDs(ctx context.Context, userID domain.UserID, tagIDs []domain.TagID) ([]*domain.TagStat, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	runner := getRunner(ctx, r.sess)
+	stats := make([]*domain.TagStat, 0, len(tagIDs))
+
+	_, err := runner.Select("rt.tag_id", "COUNT(*) AS record_count", "MAX(rc.at) AS last_used_at").From(dbr.I(recordTagTableName).As("rt")).
+		Join(dbr.I(recordTableName).As("rc"), "rc.id = rt.record_id").
+		Where(dbr.Eq("rc.user_id", userID)).
+		Where("rt.tag_id IN ?", tagIDs).
+		GroupBy("rt.tag_id").
+		LoadContext(ctx, &stats)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load tag stats by tag IDs: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ListByUserID はユーザーのすべてのTagを取得する
+func (r *TagRepository) ListByUserID(ctx context.Context, userID domain.UserID) (domain.Tags, error) {
+	runner := getRunner(ctx, r.sess)
+	tags := make([]*domain.Tag, 0)
+
+	_, err := runner.Select("*").From(tagtableName).
+		Where(dbr.Eq("user_id", userID)).
+		LoadContext(ctx, &tags)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to list tags by user ID: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetStatsByUserID はユーザーのTagのうち、使われているTagの利用状況を取得する
+func (r *TagRepository) GetStatsByUserID(ctx context.Context, userID domain.UserID) ([]*domain.TagStat, error) {
+	runner := getRunner(ctx, r.sess)
+	stats := make([]*domain.TagStat, 0)
+
+	_, err := runner.Select("rt.tag_id", "COUNT(*) AS record_count", "MAX(rc.at) AS last_used_at").From(dbr.I(recordTagTableName).As("rt")).
+		Join(dbr.I(recordTableName).As("rc"), "rc.id = rt.record_id").
+		Where(dbr.Eq("rc.user_id", userID)).
+		GroupBy("rt.tag_id").
+		LoadContext(ctx, &stats)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load tag stats by user ID: %w", err)
+	}
+
+	return stats, nil
+}
+
+// CountUsageBySimilarTitle はタイトル・説明が全文検索で一致するRecordで、Tagが使われた回数を取得する
+func (r *TagRepository) CountUsageBySimilarTitle(ctx context.Context, userID domain.UserID, title string) ([]*domain.TagUsageCount, error) {
+	runner := getRunner(ctx, r.sess)
+	counts := make([]*domain.TagUsageCount, 0)
+
+	_, err := runner.Select("rt.tag_id", "COUNT(*) AS count").From(dbr.I(recordTagTableName).As("rt")).
+		Join(dbr.I(recordTableName).As("rc"), "rc.id = rt.record_id").
+		Where(dbr.Eq("rc.user_id", userID)).
+		Where("MATCH(rc.title, rc.description) AGAINST(? IN NATURAL LANGUAGE MODE) > 0", title).
+		GroupBy("rt.tag_id").
+		LoadContext(ctx, &counts)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to count tag usage by title: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountUsageByAssetID は指定したAssetのRecordで、Tagが使われた回数を取得する
+func (r *TagRepository) CountUsageByAssetID(ctx context.Context, userID domain.UserID, assetID domain.AssetID) ([]*domain.TagUsageCount, error) {
+	runner := getRunner(ctx, r.sess)
+	counts := make([]*domain.TagUsageCount, 0)
+
+	_, err := runner.Select("rt.tag_id", "COUNT(*) AS count").From(dbr.I(recordTagTableName).As("rt")).
+		Join(dbr.I(recordTableName).As("rc"), "rc.id = rt.record_id").
+		Where(dbr.Eq("rc.user_id", userID)).
+		Where("EXISTS (SELECT 1 FROM asset_change AS ac_cond WHERE ac_cond.record_id = rc.id AND ac_cond.asset_id = ?)", assetID).
+		GroupBy("rt.tag_id").
+		LoadContext(ctx, &counts)
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to count tag usage by asset ID: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetMaxSortOrderByUserID はユーザーのTagの並び順の最大値を返す。Tagがない場合は0を返す
+func (r *TagRepository) GetMaxSortOrderByUserID(ctx context.Context, userID domain.UserID) (int, error) {
+	runner := getRunner(ctx, r.sess)
+
+	var maxSortOrder int
+	err := runner.Select("COALESCE(MAX(sort_order), 0)").From(tagtableName).
+		Where(dbr.Eq("user_id", userID)).
+		LoadOneContext(ctx, &maxSortOrder)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get max sort order of tags: %w", err)
+	}
+
+	return maxSortOrder, nil
+}
+
+func (r *TagRepository) UpdateSortOrder(ctx context.Context, userID domain.UserID, id domain.TagID, sortOrder int) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(tagtableName).
+		Set("sort_order", sortOrder).
+		Set("version", incrementVersion).
+		Where(dbr.Eq("id", id)).
+		Where(dbr.Eq("user_id", userID)).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to update sort order of tag: %w", err)
+	}
+
+	return nil
+}
+
+// ShiftSortOrderExcludingIDs は並び替えで指定されなかったTagの並び順を、offsetより後ろに現在の順のまま移す
+func (r *TagRepository) ShiftSortOrderExcludingIDs(ctx context.Context, userID domain.UserID, excludeIDs []domain.TagID, offset int) error {
+	return shiftSortOrderExcludingIDs(ctx, getRunner(ctx, r.sess), tagtableName, userID, excludeIDs, offset)
+}
+
+func tagSortColumn(sortKey domain.TagSortKey) (string, error) {
+	switch sortKey {
+	case domain.TagSortKeyName:
+		return "name", nil
+	case domain.TagSortKeyManual:
+		return "sort_order", nil
+	default:
+		return "", xerrors.Errorf("unsupported sort key for tag %s: %w", sortKey, domain.ErrInvalidPageParam)
+	}
+}
@@ -47,6 +47,19 @@ func (r *TotalAssetsSnapshotRepository) InvalidateByUserIDAndSince(ctx context.C
 	return nil
 }
 
+// DeleteByAssetID はAssetごとのスナップショットを削除する
+// 外部キーでasset_idがNULLになると全Assetのスナップショットと区別できなくなるため、Assetの削除前に呼び出す
+func (r *TotalAssetsSnapshotRepository) DeleteByAssetID(ctx context.Context, userID domain.UserID, assetID domain.AssetID) error {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.DeleteFrom(totalAssetsSnapshotTableName).
+		Where("user_id = ? AND asset_id = ?", userID, assetID).
+		Exec()
+	if err != nil {
+		return xerrors.Errorf("failed to delete total assets snapshots by asset ID: %w", err)
+	}
+	return nil
+}
+
 func (r *TotalAssetsSnapshotRepository) OptionalGetValidLatestByUserIDAndAssetIDAndBefore(ctx context.Context, userID domain.UserID, assetID *domain.AssetID, before time.Time) (*domain.TotalAssetsSnapshot, error) {
 	runner := getRunner(ctx, r.sess)
 	snapshot := &domain.TotalAssetsSnapshot{}
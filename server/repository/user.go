@@ -35,6 +35,22 @@ func (r *UserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain
 	return &user, nil
 }
 
+// LockByID はトランザクションが終わるまでユーザーの行をロックする。ユーザー単位の集計を検証してから保存するまでの間、並行した操作を待たせる
+func (r *UserRepository) LockByID(ctx context.Context, id domain.UserID) error {
+	runner := getRunner(ctx, r.sess)
+
+	var lockedID domain.UserID
+	err := runner.SelectBySql("SELECT id FROM "+usertableName+" WHERE id = ? FOR UPDATE", id).LoadOneContext(ctx, &lockedID)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return domain.ErrEntityNotFound
+		}
+		return xerrors.Errorf("failed to lock user: %w", err)
+	}
+
+	return nil
+}
+
 func (r *UserRepository) GetByName(ctx context.Context, name string) (*domain.User, error) {
 	var user domain.User
 	runner := getRunner(ctx, r.sess)
@@ -51,10 +67,23 @@ func (r *UserRepository) GetByName(ctx context.Context, name string) (*domain.Us
 
 func (r *UserRepository) Insert(ctx context.Context, user *domain.User) (*domain.User, error) {
 	runner := getRunner(ctx, r.sess)
-	_, err := runner.InsertInto(usertableName).Columns("id", "name").Record(user).Exec()
+	_, err := runner.InsertInto(usertableName).Columns("id", "name", "tax_rounding").Record(user).Exec()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to insert user: %w", err)
 	}
 
 	return user, nil
 }
+
+func (r *UserRepository) UpdateTaxRounding(ctx context.Context, user *domain.User) (*domain.User, error) {
+	runner := getRunner(ctx, r.sess)
+	_, err := runner.Update(usertableName).
+		Set("tax_rounding", user.TaxRounding).
+		Where("id = ?", user.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to update tax rounding of user: %w", err)
+	}
+
+	return user, nil
+}
@@ -3,14 +3,17 @@ package main
 import (
 	"context"
 	"kakeibo-web-server/handler/graph"
+	"kakeibo-web-server/handler/graph/errorpresenter"
 	"kakeibo-web-server/handler/graph/resolver"
 	"kakeibo-web-server/handler/middleware"
 	"kakeibo-web-server/lib/cognito"
+	"kakeibo-web-server/lib/storage"
 	"kakeibo-web-server/repository"
 	"kakeibo-web-server/usecase"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -25,7 +28,12 @@ import (
 	"github.com/go-sql-driver/mysql"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort              = "8080"
+	defaultAttachmentDir     = "attachments"
+	attachmentCleanupSpan    = time.Hour
+	attachmentMaxUploadBytes = 11 << 20 // ファイル以外のフォームの値を含めた上限
+)
 
 func main() {
 	port := os.Getenv("PORT")
@@ -51,8 +59,28 @@ func main() {
 
 	sess := dbrConn.NewSession(nil)
 
+	publicURL := os.Getenv("PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = "http://localhost:" + port
+	}
+	attachmentDir := os.Getenv("ATTACHMENT_STORAGE_DIR")
+	if attachmentDir == "" {
+		attachmentDir = defaultAttachmentDir
+	}
+	// 起動のたびに生成すると再起動や複数台の構成で発行済みのダウンロードURLが使えなくなるため、必ず指定する
+	attachmentSecret := []byte(os.Getenv("ATTACHMENT_URL_SECRET"))
+	if len(attachmentSecret) == 0 {
+		log.Fatalf("ATTACHMENT_URL_SECRET is not set")
+	}
+	attachmentStorage, err := storage.NewLocalStorage(attachmentDir, publicURL+"/files", attachmentSecret)
+	if err != nil {
+		log.Fatalf("Failed to new LocalStorage: %v", err)
+	}
+
 	repository := repository.NewRepository(sess)
-	usecase := usecase.NewUsecase(repository)
+	usecase := usecase.NewUsecase(repository, attachmentStorage)
+
+	go cleanupOrphanAttachments(usecase)
 
 	r := chi.NewRouter()
 	graphQLRouter := chi.NewRouter()
@@ -80,6 +108,12 @@ func main() {
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: attachmentMaxUploadBytes,
+		MaxMemory:     1 << 20,
+	})
+
+	srv.SetErrorPresenter(errorpresenter.Present)
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
@@ -90,7 +124,26 @@ func main() {
 	r.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	graphQLRouter.Handle("/query", srv)
 	r.Handle("/query", graphQLRouter)
+	// 署名付きURLで認証するため、Cognitoの認証を通さない
+	r.Handle("/files/*", http.StripPrefix("/files/", attachmentStorage))
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, r))
 }
+
+// cleanupOrphanAttachments は削除されたRecordの添付ファイルを定期的に削除する
+func cleanupOrphanAttachments(usecase *usecase.Usecase) {
+	ticker := time.NewTicker(attachmentCleanupSpan)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := usecase.CleanupOrphanAttachments(context.Background())
+		if err != nil {
+			log.Printf("Failed to cleanup orphan attachments: %v", err)
+			continue
+		}
+		if count > 0 {
+			log.Printf("deleted %d orphan attachments", count)
+		}
+	}
+}
@@ -7,23 +7,51 @@ import (
 	"golang.org/x/xerrors"
 )
 
-func (u *Usecase) CreateAsset(ctx context.Context, userID domain.UserID, name string, categoryID *domain.AssetCategoryID) (*domain.Asset, error) {
-	asset, err := u.repo.Asset.Insert(ctx, domain.NewAsset(userID, name, categoryID))
+// CreateAsset はAssetを作成する。並び順は既存のAssetの末尾にする
+// idempotencyKeyが作成済みのAssetと紐づいている場合は、作成せずにそのAssetを返す
+func (u *Usecase) CreateAsset(ctx context.Context, userID domain.UserID, name string, categoryID *domain.AssetCategoryID, idempotencyKey *string) (*domain.Asset, error) {
+	asset := domain.NewAsset(userID, name, categoryID)
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateAsset, []any{name, categoryID}, string(asset.ID), func(ctx context.Context) error {
+		maxSortOrder, err := u.repo.Asset.GetMaxSortOrderByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		asset.SortOrder = maxSortOrder + 1
+
+		_, err = u.repo.Asset.Insert(ctx, asset)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
+	if replayed {
+		asset, err = u.repo.Asset.GetByID(ctx, userID, domain.AssetID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
 
 	return asset, nil
 }
 
-func (u *Usecase) UpdateAsset(ctx context.Context, userID domain.UserID, id domain.AssetID, name string, categoryID *domain.AssetCategoryID) (*domain.Asset, error) {
-	asset := &domain.Asset{
-		ID:         id,
-		UserID:     userID,
-		Name:       name,
-		CategoryID: categoryID,
+// UpdateAsset はAssetを更新する。expectedVersionを指定した場合は、Assetがそのバージョンから更新されていない場合のみ更新する
+func (u *Usecase) UpdateAsset(ctx context.Context, userID domain.UserID, id domain.AssetID, name string, categoryID *domain.AssetCategoryID, expectedVersion *int) (*domain.Asset, error) {
+	asset, err := u.repo.Asset.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	err = domain.ValidateVersion(asset.Version, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
 	}
 
+	asset.Name = name
+	asset.CategoryID = categoryID
+
 	updatedAsset, err := u.repo.Asset.Update(ctx, asset)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -32,22 +60,131 @@ func (u *Usecase) UpdateAsset(ctx context.Context, userID domain.UserID, id doma
 	return updatedAsset, nil
 }
 
-func (u *Usecase) DeleteAsset(ctx context.Context, userID domain.UserID, id domain.AssetID) (domain.AssetID, error) {
-	asset := &domain.Asset{
-		ID:     id,
-		UserID: userID,
+// DeleteAsset はAssetを削除する。履歴（Record）があるAssetはcascadeがtrueの場合のみ、履歴のRecordごと削除する
+// 振替のRecordを削除すると相手のAssetの履歴と残高も変わるため、振替があるAssetはcascadeでも削除できない
+func (u *Usecase) DeleteAsset(ctx context.Context, userID domain.UserID, id domain.AssetID, cascade bool) (domain.AssetID, error) {
+	var deletedAssetID domain.AssetID
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		asset, err := u.repo.Asset.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		firstAt, err := u.repo.AssetChange.OptionalGetFirstAtByAssetID(ctx, userID, asset.ID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		if firstAt != nil {
+			if !cascade {
+				return xerrors.Errorf("asset %s cannot be deleted without cascade: %w", asset.ID, domain.ErrAssetHasHistory)
+			}
+
+			transferCount, err := u.repo.Record.CountByCondition(ctx, userID, &domain.RecordCondition{
+				AssetIDs:    []domain.AssetID{asset.ID},
+				RecordTypes: []domain.RecordType{domain.RecordTypeTransfer},
+			})
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			if transferCount > 0 {
+				return xerrors.Errorf("asset %s has %d transfer records: %w", asset.ID, transferCount, domain.ErrAssetHasTransfer)
+			}
+
+			_, err = u.repo.Record.DeleteByAssetID(ctx, userID, asset.ID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+
+			err = u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, *firstAt)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		err = u.repo.TotalAssetsSnapshot.DeleteByAssetID(ctx, userID, asset.ID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		deletedAssetID, err = u.repo.Asset.Delete(ctx, asset)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
 	}
 
-	deletedAsset, err := u.repo.Asset.Delete(ctx, asset)
+	return deletedAssetID, nil
+}
+
+// SetAssetArchived はAssetをアーカイブまたはアーカイブ解除する
+func (u *Usecase) SetAssetArchived(ctx context.Context, userID domain.UserID, id domain.AssetID, archived bool) (*domain.Asset, error) {
+	asset, err := u.repo.Asset.GetByID(ctx, userID, id)
 	if err != nil {
-		return "", xerrors.Errorf(": %w", err)
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	err = u.repo.Asset.UpdateArchived(ctx, userID, asset.ID, archived)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
 	}
+	asset.Archived = archived
+	asset.Version++
 
-	return deletedAsset, nil
+	return asset, nil
 }
 
-func (u *Usecase) GetAssetsByUserIDAndCategoryID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, categoryID *domain.AssetCategoryID) ([]*domain.Asset, *domain.PageInfo, error) {
-	assets, pageInfo, err := u.repo.Asset.GetMultiByUserIDAndCategoryID(ctx, pageParam, userID, categoryID)
+// ReorderAssets はidsの順にAssetの並び順を1から振り直す。指定しなかったAssetは現在の順のまま、指定したAssetの後ろに並べる
+func (u *Usecase) ReorderAssets(ctx context.Context, userID domain.UserID, ids []domain.AssetID) ([]*domain.Asset, error) {
+	ids = domain.UniqueAssetIDs(ids)
+
+	var assets []*domain.Asset
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getAssets, err := u.repo.Asset.GetMultiByUserIDAndIDs(ctx, userID, ids)
+		if err != nil {
+			return xerrors.Errorf("failed to get assets: %w", err)
+		}
+		if len(getAssets) != len(ids) {
+			return xerrors.Errorf("some assets not found: %w", domain.ErrEntityNotFound)
+		}
+
+		assetMap := make(map[domain.AssetID]*domain.Asset, len(getAssets))
+		for _, asset := range getAssets {
+			assetMap[asset.ID] = asset
+		}
+
+		assets = make([]*domain.Asset, 0, len(ids))
+		for i, id := range ids {
+			asset := assetMap[id]
+			asset.SortOrder = i + 1
+			asset.Version++
+
+			err = u.repo.Asset.UpdateSortOrder(ctx, userID, asset.ID, asset.SortOrder)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			assets = append(assets, asset)
+		}
+
+		err = u.repo.Asset.ShiftSortOrderExcludingIDs(ctx, userID, ids, len(ids))
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return assets, nil
+}
+
+func (u *Usecase) GetAssetsByUserIDAndCategoryID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, categoryID *domain.AssetCategoryID, includeArchived bool) ([]*domain.Asset, *domain.PageInfo, error) {
+	assets, pageInfo, err := u.repo.Asset.GetMultiByUserIDAndCategoryID(ctx, pageParam, userID, categoryID, includeArchived)
 	if err != nil {
 		return nil, nil, xerrors.Errorf(": %w", err)
 	}
@@ -55,6 +192,15 @@ func (u *Usecase) GetAssetsByUserIDAndCategoryID(ctx context.Context, pageParam
 	return assets, pageInfo, nil
 }
 
+func (u *Usecase) CountAssetsByUserIDAndCategoryID(ctx context.Context, userID domain.UserID, categoryID *domain.AssetCategoryID, includeArchived bool) (int, error) {
+	count, err := u.repo.Asset.CountByUserIDAndCategoryID(ctx, userID, categoryID, includeArchived)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
 func (u *Usecase) GetAssetsByIDs(ctx context.Context, userID domain.UserID, assetIDs []domain.AssetID) ([]*domain.Asset, error) {
 	if len(assetIDs) == 0 {
 		return nil, nil
@@ -3,12 +3,76 @@ package usecase
 import (
 	"context"
 	"kakeibo-web-server/domain"
+	"time"
 
 	"golang.org/x/xerrors"
 )
 
-func (u *Usecase) CreateAssetCategory(ctx context.Context, userID domain.UserID, name string) (*domain.AssetCategory, error) {
-	assetCategory, err := u.repo.AssetCategory.Insert(ctx, domain.NewAssetCategory(userID, name))
+func (u *Usecase) CreateAssetCategory(ctx context.Context, userID domain.UserID, name string, parentID *domain.AssetCategoryID, idempotencyKey *string) (*domain.AssetCategory, error) {
+	assetCategory := domain.NewAssetCategory(userID, name, parentID)
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateAssetCategory, []any{name, parentID}, string(assetCategory.ID), func(ctx context.Context) error {
+		if parentID != nil {
+			_, err := u.repo.AssetCategory.GetByID(ctx, userID, *parentID)
+			if err != nil {
+				return xerrors.Errorf("failed to get parent asset category: %w", err)
+			}
+		}
+
+		_, err := u.repo.AssetCategory.Insert(ctx, assetCategory)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if replayed {
+		assetCategory, err = u.repo.AssetCategory.GetByID(ctx, userID, domain.AssetCategoryID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get asset category: %w", err)
+		}
+	}
+
+	return assetCategory, nil
+}
+
+// MoveAssetCategory は親のカテゴリを変更する。parentIDがnilの場合は最上位に移動する
+func (u *Usecase) MoveAssetCategory(ctx context.Context, userID domain.UserID, id domain.AssetCategoryID, parentID *domain.AssetCategoryID) (*domain.AssetCategory, error) {
+	var assetCategory *domain.AssetCategory
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		category, err := u.repo.AssetCategory.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf("failed to get asset category: %w", err)
+		}
+		assetCategory = category
+
+		if parentID != nil {
+			_, err := u.repo.AssetCategory.GetByID(ctx, userID, *parentID)
+			if err != nil {
+				return xerrors.Errorf("failed to get parent asset category: %w", err)
+			}
+		}
+
+		categories, err := u.repo.AssetCategory.ListByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("failed to list asset categories: %w", err)
+		}
+
+		err = assetCategory.ValidateParent(parentID, categories)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		assetCategory.ParentID = parentID
+		_, err = u.repo.AssetCategory.UpdateParentID(ctx, assetCategory)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
@@ -40,8 +104,8 @@ func (u *Usecase) GetAssetCategoryByID(ctx context.Context, userID domain.UserID
 	return category, nil
 }
 
-func (u *Usecase) GetAssetCategoriesByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID) ([]*domain.AssetCategory, *domain.PageInfo, error) {
-	categories, pageInfo, err := u.repo.AssetCategory.GetMultiByUserID(ctx, pageParam, userID)
+func (u *Usecase) GetAssetCategoriesByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, rootOnly bool) ([]*domain.AssetCategory, *domain.PageInfo, error) {
+	categories, pageInfo, err := u.repo.AssetCategory.GetMultiByUserID(ctx, pageParam, userID, rootOnly)
 	if err != nil {
 		return nil, nil, xerrors.Errorf(": %w", err)
 	}
@@ -49,6 +113,15 @@ func (u *Usecase) GetAssetCategoriesByUserID(ctx context.Context, pageParam *dom
 	return categories, pageInfo, nil
 }
 
+func (u *Usecase) CountAssetCategoriesByUserID(ctx context.Context, userID domain.UserID, rootOnly bool) (int, error) {
+	count, err := u.repo.AssetCategory.CountByUserID(ctx, userID, rootOnly)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
 func (u *Usecase) GetAssetCategoriesByIDs(ctx context.Context, userID domain.UserID, assetCategoryIDs []domain.AssetCategoryID) ([]*domain.AssetCategory, error) {
 	categories, err := u.repo.AssetCategory.GetMultiByAssetCategoryIDs(ctx, userID, assetCategoryIDs)
 	if err != nil {
@@ -58,11 +131,141 @@ func (u *Usecase) GetAssetCategoriesByIDs(ctx context.Context, userID domain.Use
 	return categories, nil
 }
 
-func (u *Usecase) DeleteAssetCategory(ctx context.Context, userID domain.UserID, assetCategoryID domain.AssetCategoryID) (domain.AssetCategoryID, error) {
-	deletedCategoryID, err := u.repo.AssetCategory.Delete(ctx, userID, assetCategoryID)
+// DeleteAssetCategory はカテゴリを削除し、カテゴリの変更または解除の対象になったAssetの件数を返す
+// reparentChildrenがtrueの場合は子のカテゴリを削除するカテゴリの親に付け替え、falseの場合は子のカテゴリがあればエラーを返す
+// カテゴリに属するAssetはstrategyに従って扱う。moveToはstrategyがMOVE_TOの場合のみ使う
+func (u *Usecase) DeleteAssetCategory(ctx context.Context, userID domain.UserID, assetCategoryID domain.AssetCategoryID, reparentChildren bool, strategy domain.AssetCategoryDeleteStrategy, moveTo *domain.AssetCategoryID) (domain.AssetCategoryID, int, error) {
+	var deletedCategoryID domain.AssetCategoryID
+	affectedCount := 0
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		category, err := u.repo.AssetCategory.GetByID(ctx, userID, assetCategoryID)
+		if err != nil {
+			return xerrors.Errorf("failed to get asset category: %w", err)
+		}
+
+		switch strategy {
+		case domain.AssetCategoryDeleteStrategyRejectIfNotEmpty:
+			assetCount, err := u.repo.Asset.CountByUserIDAndCategoryID(ctx, userID, &category.ID, true)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			if assetCount > 0 {
+				return xerrors.Errorf("asset category %s has %d assets: %w", category.ID, assetCount, domain.ErrAssetCategoryNotEmpty)
+			}
+		case domain.AssetCategoryDeleteStrategyUnassign:
+			affectedCount, err = u.repo.Asset.UpdateCategoryIDByCategoryID(ctx, userID, category.ID, nil)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		case domain.AssetCategoryDeleteStrategyMoveTo:
+			if moveTo == nil || *moveTo == category.ID {
+				return xerrors.Errorf("moveTo must be another asset category: %w", domain.ErrInvalidAssetCategory)
+			}
+
+			destination, err := u.repo.AssetCategory.GetByID(ctx, userID, *moveTo)
+			if err != nil {
+				return xerrors.Errorf("failed to get destination asset category: %w", err)
+			}
+
+			affectedCount, err = u.repo.Asset.UpdateCategoryIDByCategoryID(ctx, userID, category.ID, &destination.ID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		default:
+			return xerrors.Errorf("unsupported delete strategy %s: %w", strategy, domain.ErrInvalidAssetCategory)
+		}
+
+		if reparentChildren {
+			err = u.repo.AssetCategory.UpdateParentIDByParentID(ctx, userID, category.ID, category.ParentID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		} else {
+			childCount, err := u.repo.AssetCategory.CountByParentID(ctx, userID, category.ID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			if childCount > 0 {
+				return domain.ErrAssetCategoryHasChildren
+			}
+		}
+
+		deletedCategoryID, err = u.repo.AssetCategory.Delete(ctx, userID, category.ID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", 0, xerrors.Errorf(": %w", err)
+	}
+
+	return deletedCategoryID, affectedCount, nil
+}
+
+func (u *Usecase) GetAssetCategoriesByParentIDs(ctx context.Context, userID domain.UserID, parentIDs []domain.AssetCategoryID) ([]*domain.AssetCategory, error) {
+	categories, err := u.repo.AssetCategory.GetMultiByParentIDs(ctx, userID, parentIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetAssetsByAssetCategoryIDsRecursive はカテゴリごとに、そのカテゴリと子孫のカテゴリに属するAssetを取得する
+func (u *Usecase) GetAssetsByAssetCategoryIDsRecursive(ctx context.Context, userID domain.UserID, assetCategoryIDs []domain.AssetCategoryID) (map[domain.AssetCategoryID][]*domain.Asset, error) {
+	categories, err := u.repo.AssetCategory.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	descendantIDs := make(map[domain.AssetCategoryID][]domain.AssetCategoryID, len(assetCategoryIDs))
+	allIDs := make([]domain.AssetCategoryID, 0)
+	for _, id := range assetCategoryIDs {
+		descendantIDs[id] = categories.DescendantIDs(id)
+		allIDs = append(allIDs, descendantIDs[id]...)
+	}
+
+	assets, err := u.repo.Asset.GetMultiByCategoryIDs(ctx, userID, allIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	assetsByCategoryID := make(map[domain.AssetCategoryID][]*domain.Asset)
+	for _, asset := range assets {
+		assetsByCategoryID[*asset.CategoryID] = append(assetsByCategoryID[*asset.CategoryID], asset)
+	}
+
+	result := make(map[domain.AssetCategoryID][]*domain.Asset, len(assetCategoryIDs))
+	for _, id := range assetCategoryIDs {
+		result[id] = make([]*domain.Asset, 0)
+		for _, descendantID := range descendantIDs[id] {
+			result[id] = append(result[id], assetsByCategoryID[descendantID]...)
+		}
+	}
+
+	return result, nil
+}
+
+// GetAssetCategoryTotalAmounts はカテゴリごとに、そのカテゴリと子孫のカテゴリに属するAssetの現在の残高の合計を返す
+// 未来の日時のRecordは含まない
+func (u *Usecase) GetAssetCategoryTotalAmounts(ctx context.Context, userID domain.UserID, assetCategoryIDs []domain.AssetCategoryID) (map[domain.AssetCategoryID]int, error) {
+	categories, err := u.repo.AssetCategory.ListByUserID(ctx, userID)
 	if err != nil {
-		return "", xerrors.Errorf(": %w", err)
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	amounts, err := u.repo.AssetChange.SumAmountGroupByAssetCategoryID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	result := make(map[domain.AssetCategoryID]int, len(assetCategoryIDs))
+	for _, id := range assetCategoryIDs {
+		for _, descendantID := range categories.DescendantIDs(id) {
+			result[id] += amounts[descendantID]
+		}
 	}
 
-	return deletedCategoryID, nil
+	return result, nil
 }
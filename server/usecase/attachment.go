@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"kakeibo-web-server/domain"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// attachmentCleanupBatchSize は一度の掃除で削除する添付ファイルの上限
+const attachmentCleanupBatchSize = 100
+
+// UploadAttachment はRecordにファイルを添付する
+// ファイルの種類はクライアントが送ったContent-Typeではなく、先頭のバイト列から判定する
+func (u *Usecase) UploadAttachment(ctx context.Context, userID domain.UserID, recordID domain.RecordID, fileName string, size int, body io.Reader) (*domain.Attachment, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, xerrors.Errorf("failed to read attachment: %w", err)
+	}
+	head = head[:n]
+
+	attachment, err := domain.NewAttachment(userID, recordID, fileName, http.DetectContentType(head), size)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	// 上限を超えるファイルを保存しないよう、ロックせずに先に確認する。並行した追加はトランザクション内で確認し直す
+	err = u.validateAttachmentQuota(ctx, userID, attachment.Size)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	// ファイルの保存はトランザクションに含められないため先に行い、DBへの保存に失敗したら消す
+	err = u.storage.Put(ctx, attachment.StorageKey, io.LimitReader(io.MultiReader(bytes.NewReader(head), body), int64(size)))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to put attachment: %w", err)
+	}
+
+	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		// 最初の添付ファイルでもロックできるよう、添付ファイルの行ではなくユーザーの行をロックして並行した追加で上限を超えないようにする
+		err := u.repo.User.LockByID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Record.GetByID(ctx, userID, recordID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.validateAttachmentQuota(ctx, userID, attachment.Size)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Attachment.Insert(ctx, attachment)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		deleteErr := u.storage.Delete(ctx, attachment.StorageKey)
+		if deleteErr != nil {
+			deleteErr = xerrors.Errorf("failed to delete attachment %s: %w", attachment.StorageKey, deleteErr)
+			return nil, xerrors.Errorf(": %w", errors.Join(err, deleteErr))
+		}
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachment, nil
+}
+
+// validateAttachmentQuota はsizeのファイルを追加しても上限を超えないことを確認する
+func (u *Usecase) validateAttachmentQuota(ctx context.Context, userID domain.UserID, size int) error {
+	used, err := u.repo.Attachment.SumSizeByUserID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	usage := &domain.AttachmentUsage{UsedBytes: used, QuotaBytes: domain.AttachmentQuotaPerUser}
+	err = usage.ValidateQuota(size)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAttachment は添付ファイルを削除する
+// ファイルの削除に失敗した場合はDBの行の削除もロールバックし、添付ファイルが残ったままエラーを返す
+func (u *Usecase) DeleteAttachment(ctx context.Context, userID domain.UserID, id domain.AttachmentID) (*domain.Attachment, error) {
+	var attachment *domain.Attachment
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getAttachment, err := u.repo.Attachment.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		attachment = getAttachment
+
+		err = u.repo.Attachment.Delete(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.storage.Delete(ctx, attachment.StorageKey)
+		if err != nil {
+			return xerrors.Errorf("failed to delete attachment file: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (u *Usecase) GetAttachmentsByRecordIDs(ctx context.Context, userID domain.UserID, recordIDs []domain.RecordID) ([]*domain.Attachment, error) {
+	attachments, err := u.repo.Attachment.GetMultiByRecordIDs(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentURL は添付ファイルをダウンロードするための署名付きURLを返す
+func (u *Usecase) GetAttachmentURL(ctx context.Context, attachment *domain.Attachment) (string, error) {
+	url, err := u.storage.SignedURL(ctx, attachment.StorageKey, domain.AttachmentURLExpiresIn)
+	if err != nil {
+		return "", xerrors.Errorf("failed to sign attachment URL: %w", err)
+	}
+
+	return url, nil
+}
+
+func (u *Usecase) GetAttachmentUsage(ctx context.Context, userID domain.UserID) (*domain.AttachmentUsage, error) {
+	used, err := u.repo.Attachment.SumSizeByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.AttachmentUsage{
+		UsedBytes:  used,
+		QuotaBytes: domain.AttachmentQuotaPerUser,
+	}, nil
+}
+
+// CleanupOrphanAttachments は削除されたRecordに添付されていたファイルを削除し、削除した件数を返す
+// Recordはいくつもの経路（一括削除、マージ、Assetの削除など）で削除されるため、外部キーで切り離された添付ファイルを定期的に掃除する
+func (u *Usecase) CleanupOrphanAttachments(ctx context.Context) (int, error) {
+	attachments, err := u.repo.Attachment.GetMultiOrphaned(ctx, attachmentCleanupBatchSize)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	count := 0
+	for _, attachment := range attachments {
+		err = u.storage.Delete(ctx, attachment.StorageKey)
+		if err != nil {
+			return count, xerrors.Errorf("failed to delete attachment file: %w", err)
+		}
+		err = u.repo.Attachment.Delete(ctx, attachment.UserID, attachment.ID)
+		if err != nil {
+			return count, xerrors.Errorf(": %w", err)
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+
+	"golang.org/x/xerrors"
+)
+
+// SetTagBusinessRatio はTagを付与した支出の事業割合の既定値を設定する。ratioがnilの場合は既定値をなくす
+func (u *Usecase) SetTagBusinessRatio(ctx context.Context, userID domain.UserID, id domain.TagID, ratio *int, expectedVersion *int) (*domain.Tag, error) {
+	err := domain.ValidateBusinessRatio(ratio)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	tag, err := u.repo.Tag.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	err = domain.ValidateVersion(tag.Version, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	tag.BusinessRatio = ratio
+
+	updatedTag, err := u.repo.Tag.Update(ctx, tag)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return updatedTag, nil
+}
+
+// SetAssetBusinessRatio はAssetから支払った支出の事業割合の既定値を設定する。ratioがnilの場合は既定値をなくす
+func (u *Usecase) SetAssetBusinessRatio(ctx context.Context, userID domain.UserID, id domain.AssetID, ratio *int, expectedVersion *int) (*domain.Asset, error) {
+	err := domain.ValidateBusinessRatio(ratio)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	asset, err := u.repo.Asset.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	err = domain.ValidateVersion(asset.Version, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	asset.BusinessRatio = ratio
+
+	updatedAsset, err := u.repo.Asset.Update(ctx, asset)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return updatedAsset, nil
+}
+
+// SetRecordCategoryExpenseAccount は支出の分類に青色申告決算書の科目を設定する。accountがnilの場合は親の分類の科目を使う
+func (u *Usecase) SetRecordCategoryExpenseAccount(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID, account *domain.ExpenseAccount) (*domain.RecordCategory, error) {
+	category, err := u.repo.RecordCategory.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record category: %w", err)
+	}
+	err = category.SetExpenseAccount(account)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	_, err = u.repo.RecordCategory.Update(ctx, category)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to update record category: %w", err)
+	}
+
+	return category, nil
+}
+
+// GetBusinessExpenseReport は確定申告のため、yearの支出の事業分を青色申告決算書の科目ごとに集計する
+func (u *Usecase) GetBusinessExpenseReport(ctx context.Context, userID domain.UserID, year int) (*domain.BusinessExpenseReport, error) {
+	if year < 1 || year > 9999 {
+		return nil, xerrors.Errorf("year %d is out of range: %w", year, domain.ErrInvalidDateRange)
+	}
+	from, to := domain.TaxYearRange(year)
+
+	records, err := u.repo.Record.GetMultiExpenseByAtRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	changes, err := u.repo.AssetChange.GetMultiByRecordIDs(ctx, userID, records.IDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	tags, err := u.repo.Tag.GetMultiWithRecordIDByRecordIDs(ctx, userID, records.IDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	assets, err := u.repo.Asset.GetMultiByUserIDAndIDs(ctx, userID, domain.AssetChanges(changes).AssetIDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	recordType := domain.RecordTypeExpense
+	categories, err := u.repo.RecordCategory.GetMultiByUserIDAndRecordType(ctx, userID, &recordType)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return domain.NewBusinessExpenseReport(year, records, changes, tags, assets, categories), nil
+}
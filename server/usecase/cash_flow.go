@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// GetCashFlowForecast はAssetの現在の残高に、登録済みの未来の日時のRecordと、直近historyMonthsか月の履歴から検出した毎月の入出金を加え、untilまでの残高を日ごとに予測する
+func (u *Usecase) GetCashFlowForecast(ctx context.Context, userID domain.UserID, assetID domain.AssetID, until time.Time, threshold int, historyMonths int) (*domain.CashFlowForecast, error) {
+	now := time.Now()
+	if !until.After(now) || until.Sub(now) > domain.CashFlowForecastMaxRange {
+		return nil, xerrors.Errorf("until must be within %s from now: %w", domain.CashFlowForecastMaxRange, domain.ErrInvalidDateRange)
+	}
+	if historyMonths < domain.CashFlowHistoryMonthsMin || historyMonths > domain.CashFlowHistoryMonthsMax {
+		return nil, xerrors.Errorf("history months must be between %d and %d: %w", domain.CashFlowHistoryMonthsMin, domain.CashFlowHistoryMonthsMax, domain.ErrInvalidDateRange)
+	}
+
+	_, err := u.repo.Asset.GetByID(ctx, userID, assetID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	currentBalance, err := u.CulcTotalAssetAmountAndCreateSnapshot(ctx, userID, &assetID, now, domain.RecordID(""))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	historyChanges, err := u.repo.AssetChange.GetMultiWithAtByAssetIDAndAtRange(ctx, userID, assetID, monthStart.AddDate(0, -historyMonths, 0), now)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	futureChanges, err := u.repo.AssetChange.GetMultiWithAtByAssetIDAndAtRange(ctx, userID, assetID, now, until)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	recordIDs := make([]domain.RecordID, 0, len(historyChanges)+len(futureChanges))
+	for _, changes := range []domain.AssetChangeWithAts{historyChanges, futureChanges} {
+		for _, change := range changes {
+			recordIDs = append(recordIDs, change.RecordID)
+		}
+	}
+	records, err := u.repo.Record.GetMultiByIDs(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	history := domain.NewCashFlowEntries(historyChanges, records)
+	future := domain.NewCashFlowEntries(futureChanges, records)
+	currentMonth := make([]*domain.CashFlowEntry, 0)
+	for _, entry := range history {
+		if !entry.At.Before(monthStart) {
+			currentMonth = append(currentMonth, entry)
+		}
+	}
+
+	recurring := domain.DetectRecurringCashFlows(history, monthStart, historyMonths)
+
+	return domain.NewCashFlowForecast(assetID, now, until, threshold, currentBalance, recurring, currentMonth, future), nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CreateGoal は貯蓄の目標を作成する
+func (u *Usecase) CreateGoal(ctx context.Context, userID domain.UserID, name string, targetAmount int, targetDate time.Time, assetIDs []domain.AssetID, idempotencyKey *string) (*domain.Goal, error) {
+	goal, err := domain.NewGoal(userID, name, targetAmount, targetDate, assetIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateGoal, []any{name, targetAmount, targetDate, assetIDs}, string(goal.ID), func(ctx context.Context) error {
+		err := u.validateGoalAssets(ctx, userID, goal)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Goal.Insert(ctx, goal)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if replayed {
+		goal, err = u.repo.Goal.GetByID(ctx, userID, domain.GoalID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+
+	return goal, nil
+}
+
+// UpdateGoal は目標の内容と紐付けるAssetを置き換える
+func (u *Usecase) UpdateGoal(ctx context.Context, userID domain.UserID, id domain.GoalID, name string, targetAmount int, targetDate time.Time, assetIDs []domain.AssetID) (*domain.Goal, error) {
+	var goal *domain.Goal
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getGoal, err := u.repo.Goal.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		goal = getGoal
+
+		err = goal.Update(name, targetAmount, targetDate, assetIDs)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.validateGoalAssets(ctx, userID, goal)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Goal.Update(ctx, goal)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+func (u *Usecase) DeleteGoal(ctx context.Context, userID domain.UserID, id domain.GoalID) (domain.GoalID, error) {
+	err := u.repo.Goal.Delete(ctx, userID, id)
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
+	}
+
+	return id, nil
+}
+
+func (u *Usecase) GetGoalByID(ctx context.Context, userID domain.UserID, id domain.GoalID) (*domain.Goal, error) {
+	goal, err := u.repo.Goal.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goal, nil
+}
+
+func (u *Usecase) GetGoals(ctx context.Context, userID domain.UserID) (domain.Goals, error) {
+	goals, err := u.repo.Goal.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return goals, nil
+}
+
+// GetGoalProgress は紐付けたAssetの残高から、現在の目標の進捗を計算する
+// 計画の起点は目標を作成した時点の残高で、作成より前の日時のRecordを後から登録した場合は起点の残高も変わる
+func (u *Usecase) GetGoalProgress(ctx context.Context, userID domain.UserID, goal *domain.Goal) (*domain.GoalProgress, error) {
+	now := time.Now()
+	startAmount, err := u.CulcTotalAssetAmountByAssetIDs(ctx, userID, goal.AssetIDs, goal.CreatedAt, domain.RecordID(""))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	currentAmount, err := u.CulcTotalAssetAmountByAssetIDs(ctx, userID, goal.AssetIDs, now, domain.RecordID(""))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return domain.NewGoalProgress(goal, startAmount, currentAmount, now), nil
+}
+
+// validateGoalAssets は目標に紐付けるAssetがすべてユーザーのものかを検証する
+func (u *Usecase) validateGoalAssets(ctx context.Context, userID domain.UserID, goal *domain.Goal) error {
+	assets, err := u.repo.Asset.GetMultiByUserIDAndIDs(ctx, userID, goal.AssetIDs)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	if len(assets) != len(goal.AssetIDs) {
+		return xerrors.Errorf("some assets not found: %w", domain.ErrEntityNotFound)
+	}
+
+	return nil
+}
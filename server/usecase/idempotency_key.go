@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// runIdempotent はfnをトランザクション内で実行する。keyを指定した場合は、作成するリソースのIDとキーを同じトランザクションで保存する
+// 同じキーの操作が成功済みの場合はfnを実行せず、最初の操作で作成したリソースのIDとtrueを返す。inputが最初の操作と異なる場合はErrIdempotencyKeyExistsを返す
+// 同じキーの操作が同時に実行された場合、後の操作は先の操作のトランザクションが終わるまで待ってから判定する
+func (u *Usecase) runIdempotent(ctx context.Context, userID domain.UserID, key *string, operation domain.IdempotentOperation, input any, resourceID string, fn func(ctx context.Context) error) (string, bool, error) {
+	if key == nil {
+		err := u.repo.RunInTx(ctx, fn)
+		if err != nil {
+			return "", false, xerrors.Errorf(": %w", err)
+		}
+		return resourceID, false, nil
+	}
+
+	idempotencyKey, err := domain.NewIdempotencyKey(userID, *key, operation, input, resourceID)
+	if err != nil {
+		return "", false, xerrors.Errorf(": %w", err)
+	}
+
+	// 保持期間を過ぎたキーは再利用できるように先に削除する
+	err = u.repo.IdempotencyKey.DeleteExpiredByUserID(ctx, userID, time.Now().Add(-domain.IdempotencyKeyRetention))
+	if err != nil {
+		return "", false, xerrors.Errorf(": %w", err)
+	}
+
+	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := u.repo.IdempotencyKey.Insert(ctx, idempotencyKey)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return fn(ctx)
+	})
+	if errors.Is(err, domain.ErrIdempotencyKeyExists) {
+		stored, err := u.repo.IdempotencyKey.GetByKey(ctx, userID, *key)
+		if err != nil {
+			return "", false, xerrors.Errorf(": %w", err)
+		}
+		err = stored.ValidateReplay(idempotencyKey)
+		if err != nil {
+			return "", false, xerrors.Errorf(": %w", err)
+		}
+
+		return stored.ResourceID, true, nil
+	}
+	if err != nil {
+		return "", false, xerrors.Errorf(": %w", err)
+	}
+
+	return resourceID, false, nil
+}
+
+// getRecordWithAssetChanges は再送された作成操作に対して、最初の操作で作成したRecordとAssetChangeを返す
+func (u *Usecase) getRecordWithAssetChanges(ctx context.Context, userID domain.UserID, id domain.RecordID) (*domain.Record, domain.AssetChanges, error) {
+	record, err := u.repo.Record.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to get record by ID: %w", err)
+	}
+
+	assetChanges, err := u.repo.AssetChange.GetMultiByRecordID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to get asset changes by record ID: %w", err)
+	}
+
+	return record, assetChanges, nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// GetItemPriceHistories はfrom以降to未満の支出から、queryにあいまいに一致する品物の価格の推移を品名ごとに返す
+func (u *Usecase) GetItemPriceHistories(ctx context.Context, userID domain.UserID, query string, from, to time.Time) ([]*domain.ItemPriceHistory, error) {
+	err := domain.ValidateItemPriceQuery(query)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if to.Before(from) || to.Sub(from) > domain.ItemPriceHistoryMaxRange {
+		return nil, xerrors.Errorf("range must be within %s: %w", domain.ItemPriceHistoryMaxRange, domain.ErrInvalidDateRange)
+	}
+
+	prices, err := u.repo.Record.GetItemPricesByAtRange(ctx, userID, from, to, domain.ItemPriceCandidateChars(query))
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return domain.NewItemPriceHistories(query, prices), nil
+}
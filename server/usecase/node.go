@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+
+	"golang.org/x/xerrors"
+)
+
+// GetNodesByIDs はIDの種別（IDSuffix）ごとにエンティティをまとめて取得し、idsと同じ順序で返す
+// 見つからないIDや種別を判別できないIDに対応する要素はnilになる
+func (u *Usecase) GetNodesByIDs(ctx context.Context, userID domain.UserID, ids []domain.ID) ([]domain.Node, error) {
+	assetIDs := make([]domain.AssetID, 0)
+	assetCategoryIDs := make([]domain.AssetCategoryID, 0)
+	tagIDs := make([]domain.TagID, 0)
+	recordIDs := make([]domain.RecordID, 0)
+	recordCategoryIDs := make([]domain.RecordCategoryID, 0)
+	payeeIDs := make([]domain.PayeeID, 0)
+	goalIDs := make([]domain.GoalID, 0)
+
+	for _, id := range ids {
+		switch id.Suffix() {
+		case domain.AssetIDSuffix:
+			assetIDs = append(assetIDs, domain.AssetID(id))
+		case domain.AssetCategoryIDSuffix:
+			assetCategoryIDs = append(assetCategoryIDs, domain.AssetCategoryID(id))
+		case domain.TagIDSuffix:
+			tagIDs = append(tagIDs, domain.TagID(id))
+		case domain.RecordIDSuffix:
+			recordIDs = append(recordIDs, domain.RecordID(id))
+		case domain.RecordCategoryIDSuffix:
+			recordCategoryIDs = append(recordCategoryIDs, domain.RecordCategoryID(id))
+		case domain.PayeeIDSuffix:
+			payeeIDs = append(payeeIDs, domain.PayeeID(id))
+		case domain.GoalIDSuffix:
+			goalIDs = append(goalIDs, domain.GoalID(id))
+		}
+	}
+
+	nodes := make(map[domain.ID]domain.Node, len(ids))
+
+	assets, err := u.repo.Asset.GetMultiByUserIDAndIDs(ctx, userID, assetIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, asset := range assets {
+		nodes[domain.ID(asset.ID)] = asset
+	}
+
+	assetCategories, err := u.repo.AssetCategory.GetMultiByAssetCategoryIDs(ctx, userID, assetCategoryIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, assetCategory := range assetCategories {
+		nodes[domain.ID(assetCategory.ID)] = assetCategory
+	}
+
+	tags, err := u.repo.Tag.GetMultiByIDs(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, tag := range tags {
+		nodes[domain.ID(tag.ID)] = tag
+	}
+
+	records, err := u.repo.Record.GetMultiByIDs(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, record := range records {
+		nodes[domain.ID(record.ID)] = record
+	}
+
+	recordCategories, err := u.repo.RecordCategory.GetMultiByIDs(ctx, userID, recordCategoryIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, recordCategory := range recordCategories {
+		nodes[domain.ID(recordCategory.ID)] = recordCategory
+	}
+
+	payees, err := u.repo.Payee.GetMultiByIDs(ctx, userID, payeeIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, payee := range payees {
+		nodes[domain.ID(payee.ID)] = payee
+	}
+
+	goals, err := u.repo.Goal.GetMultiByIDs(ctx, userID, goalIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for _, goal := range goals {
+		nodes[domain.ID(goal.ID)] = goal
+	}
+
+	// UserのIDはCognitoのsubをそのまま使っており種別が付与されていないため、ログイン中のユーザーのIDと一致する場合のみ返す
+	for _, id := range ids {
+		if id == domain.ID(userID) {
+			user, err := u.repo.User.GetByID(ctx, userID)
+			if err != nil {
+				return nil, xerrors.Errorf(": %w", err)
+			}
+			nodes[id] = user
+			break
+		}
+	}
+
+	result := make([]domain.Node, len(ids))
+	for i, id := range ids {
+		if node, ok := nodes[id]; ok {
+			result[i] = node
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// CreatePayee はPayeeを作成する。名前・別名は他のPayeeと重複できない
+func (u *Usecase) CreatePayee(ctx context.Context, userID domain.UserID, name string, aliases []string, idempotencyKey *string) (*domain.Payee, error) {
+	payee, err := domain.NewPayee(userID, name, aliases)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreatePayee, []any{name, aliases}, string(payee.ID), func(ctx context.Context) error {
+		// 正規化した名前・別名の重複はUNIQUE制約で防げないため、ユーザーの行をロックして並行した作成・更新を直列にする
+		err := u.repo.User.LockByID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		payees, err := u.repo.Payee.ListByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = payees.ValidateNames(payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Payee.Insert(ctx, payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if replayed {
+		payee, err = u.repo.Payee.GetByID(ctx, userID, domain.PayeeID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+
+	return payee, nil
+}
+
+// UpdatePayee はPayeeの名前と別名を置き換える
+func (u *Usecase) UpdatePayee(ctx context.Context, userID domain.UserID, id domain.PayeeID, name string, aliases []string) (*domain.Payee, error) {
+	var payee *domain.Payee
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		err := u.repo.User.LockByID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		payees, err := u.repo.Payee.ListByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		for _, p := range payees {
+			if p.ID == id {
+				payee = p
+			}
+		}
+		if payee == nil {
+			return xerrors.Errorf("payee %s: %w", id, domain.ErrEntityNotFound)
+		}
+
+		err = payee.Rename(name, aliases)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = payees.ValidateNames(payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		_, err = u.repo.Payee.Update(ctx, payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+// DeletePayee はPayeeを削除する。このPayeeを設定していたRecordは支払先なしになる
+func (u *Usecase) DeletePayee(ctx context.Context, userID domain.UserID, id domain.PayeeID) (domain.PayeeID, error) {
+	err := u.repo.Payee.Delete(ctx, userID, id)
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
+	}
+
+	return id, nil
+}
+
+func (u *Usecase) GetPayeeByID(ctx context.Context, userID domain.UserID, id domain.PayeeID) (*domain.Payee, error) {
+	payee, err := u.repo.Payee.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payee, nil
+}
+
+func (u *Usecase) GetPayees(ctx context.Context, userID domain.UserID) (domain.Payees, error) {
+	payees, err := u.repo.Payee.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees, nil
+}
+
+func (u *Usecase) GetPayeesByIDs(ctx context.Context, userID domain.UserID, ids []domain.PayeeID) (domain.Payees, error) {
+	payees, err := u.repo.Payee.GetMultiByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees, nil
+}
+
+// GetPayeeSpending はfrom以降to未満の支出をPayeeごとに集計し、金額の大きい順に返す
+func (u *Usecase) GetPayeeSpending(ctx context.Context, userID domain.UserID, from, to time.Time) ([]*domain.PayeeSpending, error) {
+	if !from.Before(to) {
+		return nil, xerrors.Errorf("from must be before to: %w", domain.ErrInvalidDateRange)
+	}
+
+	amounts, err := u.repo.Record.SumExpenseByConditionGroupByPayee(ctx, userID, &domain.RecordCondition{AtFrom: &from, AtTo: &to})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	payeeIDs := make([]domain.PayeeID, 0, len(amounts))
+	for _, amount := range amounts {
+		if amount.PayeeID != nil {
+			payeeIDs = append(payeeIDs, *amount.PayeeID)
+		}
+	}
+	payees, err := u.repo.Payee.GetMultiByIDs(ctx, userID, payeeIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	payeeMap := make(map[domain.PayeeID]*domain.Payee, len(payees))
+	for _, payee := range payees {
+		payeeMap[payee.ID] = payee
+	}
+
+	spendings := make([]*domain.PayeeSpending, 0, len(amounts))
+	for _, amount := range amounts {
+		spending := &domain.PayeeSpending{
+			Amount:      amount.Amount,
+			RecordCount: amount.RecordCount,
+		}
+		if amount.PayeeID != nil {
+			spending.Payee = payeeMap[*amount.PayeeID]
+		}
+		spendings = append(spendings, spending)
+	}
+
+	return spendings, nil
+}
+
+// updateRecordPayee はRecordの支払先を変更する。payeeIDがnilの場合はclearPayeeがtrueのときのみ支払先なしにする
+// どちらも指定せずタイトルを変更した場合は、新しいタイトルに一致するPayeeがあればそれに変更する
+func (u *Usecase) updateRecordPayee(ctx context.Context, userID domain.UserID, record *domain.Record, payeeID *domain.PayeeID, clearPayee bool, titleChanged bool) error {
+	if payeeID != nil && clearPayee {
+		return xerrors.Errorf("payeeID and clearPayee cannot be specified together: %w", domain.ErrInvalidPayee)
+	}
+	if clearPayee {
+		return record.SetPayee(nil)
+	}
+	if payeeID == nil && !titleChanged {
+		return nil
+	}
+
+	payee, err := u.getPayeeForRecord(ctx, userID, payeeID, record.Title)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	if payee == nil {
+		return nil
+	}
+	err = record.SetPayee(payee)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	return nil
+}
+
+// getPayeeForRecord はRecordに設定するPayeeを返す
+// payeeIDを指定しない場合は、タイトルに一致する名前・別名を持つPayeeを探し、見つからなければnilを返す
+func (u *Usecase) getPayeeForRecord(ctx context.Context, userID domain.UserID, payeeID *domain.PayeeID, title string) (*domain.Payee, error) {
+	if payeeID != nil {
+		payee, err := u.repo.Payee.GetByID(ctx, userID, *payeeID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get payee: %w", err)
+		}
+		return payee, nil
+	}
+
+	payees, err := u.repo.Payee.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return payees.Match(title), nil
+}
@@ -8,13 +8,36 @@ import (
 	"golang.org/x/xerrors"
 )
 
-func (u *Usecase) CreateIncomeRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string) (*domain.Record, *domain.AssetChange, error) {
+func (u *Usecase) CreateIncomeRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string, categoryID *domain.RecordCategoryID, payeeID *domain.PayeeID, taxRate *domain.TaxRate, idempotencyKey *string) (*domain.Record, *domain.AssetChange, error) {
 	record, assetChange, err := domain.NewRecordIncomeWithAssetChange(userID, title, description, at, assetID, amount)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateIncomeRecord, []any{title, description, at, assetID, amount, tagNames, categoryID, payeeID, taxRate}, string(record.ID), func(ctx context.Context) error {
+		category, err := u.getRecordCategoryForRecord(ctx, userID, categoryID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = record.SetCategory(category)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		payee, err := u.getPayeeForRecord(ctx, userID, payeeID, title)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = record.SetPayee(payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.updateRecordTaxRate(ctx, userID, record, taxRate, false)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		_, err = u.repo.Record.Insert(ctx, record)
 		if err != nil {
 			return xerrors.Errorf("failed to insert record: %w", err)
@@ -42,17 +65,47 @@ func (u *Usecase) CreateIncomeRecord(ctx context.Context, userID domain.UserID,
 	if err != nil {
 		return nil, nil, xerrors.Errorf(": %w", err)
 	}
+	if replayed {
+		record, assetChanges, err := u.getRecordWithAssetChanges(ctx, userID, domain.RecordID(resourceID))
+		if err != nil {
+			return nil, nil, xerrors.Errorf(": %w", err)
+		}
+		return record, assetChanges.Income(), nil
+	}
 
 	return record, assetChange, nil
 }
 
-func (u *Usecase) CreateExpenseRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string) (*domain.Record, *domain.AssetChange, error) {
+func (u *Usecase) CreateExpenseRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string, categoryID *domain.RecordCategoryID, payeeID *domain.PayeeID, taxRate *domain.TaxRate, idempotencyKey *string) (*domain.Record, *domain.AssetChange, error) {
 	record, assetChange, err := domain.NewRecordExpenseWithAssetChange(userID, title, description, at, assetID, amount)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateExpenseRecord, []any{title, description, at, assetID, amount, tagNames, categoryID, payeeID, taxRate}, string(record.ID), func(ctx context.Context) error {
+		category, err := u.getRecordCategoryForRecord(ctx, userID, categoryID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = record.SetCategory(category)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		payee, err := u.getPayeeForRecord(ctx, userID, payeeID, title)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = record.SetPayee(payee)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.updateRecordTaxRate(ctx, userID, record, taxRate, false)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		_, err = u.repo.Record.Insert(ctx, record)
 		if err != nil {
 			return xerrors.Errorf("failed to insert record: %w", err)
@@ -79,17 +132,24 @@ func (u *Usecase) CreateExpenseRecord(ctx context.Context, userID domain.UserID,
 	if err != nil {
 		return nil, nil, xerrors.Errorf(": %w", err)
 	}
+	if replayed {
+		record, assetChanges, err := u.getRecordWithAssetChanges(ctx, userID, domain.RecordID(resourceID))
+		if err != nil {
+			return nil, nil, xerrors.Errorf(": %w", err)
+		}
+		return record, assetChanges.Expense(), nil
+	}
 
 	return record, assetChange, nil
 }
 
-func (u *Usecase) CreateTransferRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, fromAssetID domain.AssetID, toAssetID domain.AssetID, amount int, tagNames []string) (*domain.Record, *domain.AssetChange, *domain.AssetChange, error) {
+func (u *Usecase) CreateTransferRecord(ctx context.Context, userID domain.UserID, title string, description string, at time.Time, fromAssetID domain.AssetID, toAssetID domain.AssetID, amount int, tagNames []string, idempotencyKey *string) (*domain.Record, *domain.AssetChange, *domain.AssetChange, error) {
 	record, fromAssetChange, toAssetChange, err := domain.NewRecordTransferWithAssetChanges(userID, title, description, at, fromAssetID, toAssetID, amount)
 	if err != nil {
 		return nil, nil, nil, xerrors.Errorf("failed to create transfer record: %w", err)
 	}
 
-	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateTransferRecord, []any{title, description, at, fromAssetID, toAssetID, amount, tagNames}, string(record.ID), func(ctx context.Context) error {
 		_, err = u.repo.Record.Insert(ctx, record)
 		if err != nil {
 			return xerrors.Errorf("failed to insert record: %w", err)
@@ -120,24 +180,51 @@ func (u *Usecase) CreateTransferRecord(ctx context.Context, userID domain.UserID
 	if err != nil {
 		return nil, nil, nil, xerrors.Errorf(": %w", err)
 	}
+	if replayed {
+		record, assetChanges, err := u.getRecordWithAssetChanges(ctx, userID, domain.RecordID(resourceID))
+		if err != nil {
+			return nil, nil, nil, xerrors.Errorf(": %w", err)
+		}
+		return record, assetChanges.Expense(), assetChanges.Income(), nil
+	}
 
 	return record, fromAssetChange, toAssetChange, nil
 }
 
-func (u *Usecase) UpdateIncomeRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string) (*domain.Record, error) {
+// UpdateIncomeRecord は収入のRecordを更新する。categoryIDを指定せずclearCategoryがfalseの場合は分類を変更しない
+// 支払先はpayeeIDを指定せずclearPayeeがfalseの場合、タイトルを変更したときのみ新しいタイトルに一致するPayeeに変更する
+func (u *Usecase) UpdateIncomeRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string, categoryID *domain.RecordCategoryID, clearCategory bool, payeeID *domain.PayeeID, clearPayee bool, taxRate *domain.TaxRate, clearTaxRate bool, expectedVersion *int) (*domain.Record, error) {
 	var record *domain.Record
 	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
 		getRecord, err := u.repo.Record.GetByID(ctx, userID, id)
 		if err != nil {
 			return xerrors.Errorf("failed to get record by ID: %w", err)
 		}
+		err = domain.ValidateVersion(getRecord.Version, expectedVersion)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
 
 		record = getRecord
 
+		titleChanged := record.Title != title
 		record.Title = title
 		record.Description = description
 		record.At = at
 
+		err = u.updateRecordCategory(ctx, userID, record, categoryID, clearCategory)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.updateRecordPayee(ctx, userID, record, payeeID, clearPayee, titleChanged)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.updateRecordTaxRate(ctx, userID, record, taxRate, clearTaxRate)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		_, err = u.repo.Record.Update(ctx, record)
 		if err != nil {
 			return xerrors.Errorf("failed to update record: %w", err)
@@ -177,6 +264,11 @@ func (u *Usecase) UpdateIncomeRecord(ctx context.Context, userID domain.UserID,
 			}
 		}
 
+		err = u.validateRecordItems(ctx, userID, record)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, record.At)
 
 		return nil
@@ -188,20 +280,40 @@ func (u *Usecase) UpdateIncomeRecord(ctx context.Context, userID domain.UserID,
 	return record, nil
 }
 
-func (u *Usecase) UpdateExpenseRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string) (*domain.Record, error) {
+// UpdateExpenseRecord は支出のRecordを更新する。categoryIDを指定せずclearCategoryがfalseの場合は分類を変更しない
+// 支払先はpayeeIDを指定せずclearPayeeがfalseの場合、タイトルを変更したときのみ新しいタイトルに一致するPayeeに変更する
+func (u *Usecase) UpdateExpenseRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, assetID domain.AssetID, amount int, tagNames []string, categoryID *domain.RecordCategoryID, clearCategory bool, payeeID *domain.PayeeID, clearPayee bool, taxRate *domain.TaxRate, clearTaxRate bool, expectedVersion *int) (*domain.Record, error) {
 	var record *domain.Record
 	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
 		getRecord, err := u.repo.Record.GetByID(ctx, userID, id)
 		if err != nil {
 			return xerrors.Errorf("failed to get record by ID: %w", err)
 		}
+		err = domain.ValidateVersion(getRecord.Version, expectedVersion)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
 
 		record = getRecord
 
+		titleChanged := record.Title != title
 		record.Title = title
 		record.Description = description
 		record.At = at
 
+		err = u.updateRecordCategory(ctx, userID, record, categoryID, clearCategory)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.updateRecordPayee(ctx, userID, record, payeeID, clearPayee, titleChanged)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.updateRecordTaxRate(ctx, userID, record, taxRate, clearTaxRate)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		_, err = u.repo.Record.Update(ctx, record)
 		if err != nil {
 			return xerrors.Errorf("failed to update record: %w", err)
@@ -240,6 +352,11 @@ func (u *Usecase) UpdateExpenseRecord(ctx context.Context, userID domain.UserID,
 			}
 		}
 
+		err = u.validateRecordItems(ctx, userID, record)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, record.At)
 
 		return nil
@@ -251,13 +368,17 @@ func (u *Usecase) UpdateExpenseRecord(ctx context.Context, userID domain.UserID,
 	return record, nil
 }
 
-func (u *Usecase) UpdateTransferRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, fromAssetID domain.AssetID, toAssetID domain.AssetID, amount int, tagNames []string) (*domain.Record, error) {
+func (u *Usecase) UpdateTransferRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, title string, description string, at time.Time, fromAssetID domain.AssetID, toAssetID domain.AssetID, amount int, tagNames []string, expectedVersion *int) (*domain.Record, error) {
 	var record *domain.Record
 	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
 		getRecord, err := u.repo.Record.GetByID(ctx, userID, id)
 		if err != nil {
 			return xerrors.Errorf("failed to get record by ID: %w", err)
 		}
+		err = domain.ValidateVersion(getRecord.Version, expectedVersion)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
 
 		record = getRecord
 
@@ -307,6 +428,11 @@ func (u *Usecase) UpdateTransferRecord(ctx context.Context, userID domain.UserID
 			}
 		}
 
+		err = u.validateRecordItems(ctx, userID, record)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
 		u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, record.At)
 
 		return nil
@@ -350,8 +476,8 @@ func (u *Usecase) GetRecordByID(ctx context.Context, userID domain.UserID, id do
 	return record, nil
 }
 
-func (u *Usecase) GetRecordsByUserIDAndAssetID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, assetID *domain.AssetID) (domain.Records, *domain.PageInfo, error) {
-	records, pageInfo, err := u.repo.Record.GetMultiByUserIDAndAssetID(ctx, pageParam, userID, assetID)
+func (u *Usecase) GetRecordsByCondition(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, cond *domain.RecordCondition) (domain.Records, *domain.PageInfo, error) {
+	records, pageInfo, err := u.repo.Record.GetMultiByCondition(ctx, pageParam, userID, cond)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to get records: %w", err)
 	}
@@ -393,11 +519,197 @@ func (u *Usecase) CulcTotalAssetAmountAndCreateSnapshot(ctx context.Context, use
 	return totalAssetsAmount, nil
 }
 
-func (u *Usecase) GetRecordsPerMonth(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, year int, month int, tagNames []string, assetIDs []domain.AssetID, recordTypes []domain.RecordType) (domain.Records, *domain.PageInfo, error) {
-	records, pageInfo, err := u.repo.Record.GetMultiByUserIDAndYearAndMonth(ctx, pageParam, userID, year, month, tagNames, assetIDs, recordTypes)
+// CulcTotalAssetAmountByAssetIDs はassetIDsのAssetの資産合計をAssetごとに求めて合計する。assetIDsが空の場合はすべてのAssetの合計を返す
+func (u *Usecase) CulcTotalAssetAmountByAssetIDs(ctx context.Context, userID domain.UserID, assetIDs []domain.AssetID, before time.Time, recordID domain.RecordID) (int, error) {
+	if len(assetIDs) == 0 {
+		amount, err := u.CulcTotalAssetAmountAndCreateSnapshot(ctx, userID, nil, before, recordID)
+		if err != nil {
+			return 0, xerrors.Errorf(": %w", err)
+		}
+		return amount, nil
+	}
+
+	total := 0
+	seen := make(map[domain.AssetID]bool, len(assetIDs))
+	for _, assetID := range assetIDs {
+		if seen[assetID] {
+			continue
+		}
+		seen[assetID] = true
+
+		amount, err := u.CulcTotalAssetAmountAndCreateSnapshot(ctx, userID, &assetID, before, recordID)
+		if err != nil {
+			return 0, xerrors.Errorf(": %w", err)
+		}
+		total += amount
+	}
+
+	return total, nil
+}
+
+func (u *Usecase) CountRecordsByCondition(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition) (int, error) {
+	count, err := u.repo.Record.CountByCondition(ctx, userID, cond)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count records: %w", err)
+	}
+
+	return count, nil
+}
+
+// SumIncomeByCondition は条件に一致する収入Recordの金額を合計する（振替は含まない）
+func (u *Usecase) SumIncomeByCondition(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition) (int, error) {
+	sum, err := u.repo.Record.SumAmountByConditionAndRecordType(ctx, userID, cond, domain.RecordTypeIncome)
 	if err != nil {
-		return nil, nil, xerrors.Errorf("failed to get records per month: %w", err)
+		return 0, xerrors.Errorf("failed to sum income: %w", err)
+	}
+
+	return sum, nil
+}
+
+// SumExpenseByCondition は条件に一致する支出Recordの金額を正の値で合計する（振替は含まない）
+func (u *Usecase) SumExpenseByCondition(ctx context.Context, userID domain.UserID, cond *domain.RecordCondition) (int, error) {
+	sum, err := u.repo.Record.SumAmountByConditionAndRecordType(ctx, userID, cond, domain.RecordTypeExpense)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to sum expense: %w", err)
+	}
+
+	return -sum, nil
+}
+
+// SearchRecords は全文検索に一致するRecordを関連度の高い順に取得する
+func (u *Usecase) SearchRecords(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, query string, cond *domain.RecordCondition) ([]*domain.RecordWithScore, *domain.PageInfo, error) {
+	if len(domain.SearchTerms(query)) == 0 {
+		return nil, nil, domain.ErrEmptySearchQuery
+	}
+
+	records, pageInfo, err := u.repo.Record.Search(ctx, pageParam, userID, query, cond)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to search records: %w", err)
 	}
 
 	return records, pageInfo, nil
 }
+
+func (u *Usecase) CountSearchRecords(ctx context.Context, userID domain.UserID, query string, cond *domain.RecordCondition) (int, error) {
+	count, err := u.repo.Record.CountSearch(ctx, userID, query, cond)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to count searched records: %w", err)
+	}
+
+	return count, nil
+}
+
+// PatchRecord はpatchで指定した項目のみRecordを更新する。種別を変換した場合はAssetChangeを変換後の種別に合わせる
+func (u *Usecase) PatchRecord(ctx context.Context, userID domain.UserID, id domain.RecordID, patch *domain.RecordPatch, expectedVersion *int) (*domain.Record, error) {
+	var record *domain.Record
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getRecord, err := u.repo.Record.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf("failed to get record by ID: %w", err)
+		}
+		err = domain.ValidateVersion(getRecord.Version, expectedVersion)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		record = getRecord
+		beforeAt := record.At
+
+		assetChanges, err := u.repo.AssetChange.GetMultiByRecordID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf("failed to get asset changes by record ID: %w", err)
+		}
+
+		if patch.TaxRate != nil || patch.TaxMode != nil {
+			rounding, err := u.getTaxRounding(ctx, userID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			patch.TaxRounding = &rounding
+		}
+
+		plan, err := patch.Apply(record, assetChanges)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		if patch.CategoryID != nil {
+			category, err := u.getRecordCategoryForRecord(ctx, userID, patch.CategoryID)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			err = record.SetCategory(category)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		if patch.PayeeID != nil {
+			payee, err := u.getPayeeForRecord(ctx, userID, patch.PayeeID, record.Title)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			err = record.SetPayee(payee)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		_, err = u.repo.Record.Update(ctx, record)
+		if err != nil {
+			return xerrors.Errorf("failed to update record: %w", err)
+		}
+
+		for _, change := range plan.Updates {
+			_, err = u.repo.AssetChange.Update(ctx, change)
+			if err != nil {
+				return xerrors.Errorf("failed to update asset change: %w", err)
+			}
+		}
+		for _, change := range plan.Inserts {
+			_, err = u.repo.AssetChange.Insert(ctx, change)
+			if err != nil {
+				return xerrors.Errorf("failed to insert asset change: %w", err)
+			}
+		}
+		for _, change := range plan.Deletes {
+			err = u.repo.AssetChange.Delete(ctx, userID, change.ID)
+			if err != nil {
+				return xerrors.Errorf("failed to delete asset change: %w", err)
+			}
+		}
+
+		if patch.TagNames != nil {
+			tags, err := u.GetOrCreateTagsByName(ctx, userID, patch.TagNames)
+			if err != nil {
+				return xerrors.Errorf("failed to get or create tags: %w", err)
+			}
+			err = u.repo.RecordTag.DeleteByRecordID(ctx, record.ID)
+			if err != nil {
+				return xerrors.Errorf("failed to delete record tags: %w", err)
+			}
+			for _, tag := range tags {
+				err = u.repo.RecordTag.Insert(ctx, record.ID, tag.ID)
+				if err != nil {
+					return xerrors.Errorf("failed to insert record tag: %w", err)
+				}
+			}
+		}
+
+		err = u.validateRecordItems(ctx, userID, record)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, domain.EarliestAt(beforeAt, record.At))
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// errRecordBulkFailed は一括操作でいずれかのRecordの操作に失敗し、トランザクションをロールバックするときに返す
+var errRecordBulkFailed = xerrors.New("record bulk operation failed")
+
+// BulkUpdateRecords は複数のRecordにまとめて変更を適用する
+// いずれかのRecordに適用できない場合はすべての変更を取り消し、Recordごとの失敗理由を返す
+func (u *Usecase) BulkUpdateRecords(ctx context.Context, userID domain.UserID, ids []domain.RecordID, patch *domain.RecordBulkPatch) (*domain.BulkRecordsPayload, error) {
+	ids = domain.UniqueRecordIDs(ids)
+	err := validateRecordBulkIDs(ids)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if patch.IsEmpty() {
+		return nil, xerrors.Errorf("patch must have at least one change: %w", domain.ErrInvalidRecordBulkPatch)
+	}
+
+	var results []*domain.RecordBulkResult
+	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		results = make([]*domain.RecordBulkResult, 0, len(ids))
+
+		if patch.AssetID != nil {
+			_, err := u.repo.Asset.GetByID(ctx, userID, *patch.AssetID)
+			if err != nil {
+				return xerrors.Errorf("failed to get asset: %w", err)
+			}
+		}
+
+		recordMap, changeMap, err := u.getRecordsWithAssetChanges(ctx, userID, ids)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		failed := false
+		var earliestAt time.Time
+		for _, id := range ids {
+			record, ok := recordMap[id]
+			if !ok {
+				results = append(results, &domain.RecordBulkResult{ID: id, Err: domain.ErrEntityNotFound})
+				failed = true
+				continue
+			}
+
+			beforeAt := record.At
+			err := patch.Apply(record, changeMap[id])
+			if err != nil {
+				results = append(results, &domain.RecordBulkResult{ID: id, Err: err})
+				failed = true
+				continue
+			}
+			earliestAt = domain.EarliestAt(earliestAt, beforeAt, record.At)
+
+			results = append(results, &domain.RecordBulkResult{ID: id, Record: record})
+		}
+		if failed {
+			return errRecordBulkFailed
+		}
+
+		for _, id := range ids {
+			_, err = u.repo.Record.Update(ctx, recordMap[id])
+			if err != nil {
+				return xerrors.Errorf("failed to update record: %w", err)
+			}
+
+			if patch.AssetID != nil {
+				for _, change := range changeMap[id] {
+					_, err = u.repo.AssetChange.Update(ctx, change)
+					if err != nil {
+						return xerrors.Errorf("failed to update asset change: %w", err)
+					}
+				}
+			}
+		}
+
+		if len(patch.AddTagNames) > 0 {
+			tags, err := u.GetOrCreateTagsByName(ctx, userID, patch.AddTagNames)
+			if err != nil {
+				return xerrors.Errorf("failed to get or create tags: %w", err)
+			}
+			err = u.repo.RecordTag.InsertMulti(ctx, ids, domain.Tags(tags).IDs())
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		if len(patch.RemoveTagNames) > 0 {
+			tags, err := u.repo.Tag.GetMultiByNames(ctx, userID, domain.NormalizeTagNames(patch.RemoveTagNames))
+			if err != nil {
+				return xerrors.Errorf("failed to get tags: %w", err)
+			}
+			err = u.repo.RecordTag.DeleteByRecordIDsAndTagIDs(ctx, ids, tags.IDs())
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		if patch.ChangesAssetAmount() {
+			err = u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, earliestAt)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		return nil
+	})
+	if errors.Is(err, errRecordBulkFailed) {
+		return domain.NewFailedBulkRecordsPayload(results), nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.BulkRecordsPayload{
+		Results:   results,
+		Succeeded: true,
+	}, nil
+}
+
+// BulkDeleteRecords は複数のRecordをまとめて削除する
+// 存在しないRecordが含まれる場合はいずれのRecordも削除せず、Recordごとの失敗理由を返す
+func (u *Usecase) BulkDeleteRecords(ctx context.Context, userID domain.UserID, ids []domain.RecordID) (*domain.BulkRecordsPayload, error) {
+	ids = domain.UniqueRecordIDs(ids)
+	err := validateRecordBulkIDs(ids)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	var results []*domain.RecordBulkResult
+	err = u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		results = make([]*domain.RecordBulkResult, 0, len(ids))
+
+		records, err := u.repo.Record.GetMultiByIDs(ctx, userID, ids)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		recordMap := make(map[domain.RecordID]*domain.Record, len(records))
+		for _, record := range records {
+			recordMap[record.ID] = record
+		}
+
+		failed := false
+		var earliestAt time.Time
+		for _, id := range ids {
+			record, ok := recordMap[id]
+			if !ok {
+				results = append(results, &domain.RecordBulkResult{ID: id, Err: domain.ErrEntityNotFound})
+				failed = true
+				continue
+			}
+			earliestAt = domain.EarliestAt(earliestAt, record.At)
+
+			results = append(results, &domain.RecordBulkResult{ID: id})
+		}
+		if failed {
+			return errRecordBulkFailed
+		}
+
+		_, err = u.repo.Record.DeleteByIDs(ctx, userID, ids)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		err = u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, earliestAt)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if errors.Is(err, errRecordBulkFailed) {
+		return domain.NewFailedBulkRecordsPayload(results), nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return &domain.BulkRecordsPayload{
+		Results:   results,
+		Succeeded: true,
+	}, nil
+}
+
+func validateRecordBulkIDs(ids []domain.RecordID) error {
+	if len(ids) == 0 || len(ids) > domain.RecordBulkMaxCount {
+		return xerrors.Errorf("number of records must be between 1 and %d: %w", domain.RecordBulkMaxCount, domain.ErrInvalidRecordBulkPatch)
+	}
+
+	return nil
+}
+
+// getRecordsWithAssetChanges はRecordとそのAssetChangeをRecordのIDごとにまとめて取得する
+func (u *Usecase) getRecordsWithAssetChanges(ctx context.Context, userID domain.UserID, ids []domain.RecordID) (map[domain.RecordID]*domain.Record, map[domain.RecordID]domain.AssetChanges, error) {
+	records, err := u.repo.Record.GetMultiByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, nil, xerrors.Errorf(": %w", err)
+	}
+	recordMap := make(map[domain.RecordID]*domain.Record, len(records))
+	for _, record := range records {
+		recordMap[record.ID] = record
+	}
+
+	changes, err := u.repo.AssetChange.GetMultiByRecordIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, nil, xerrors.Errorf(": %w", err)
+	}
+	changeMap := make(map[domain.RecordID]domain.AssetChanges, len(records))
+	for _, change := range changes {
+		changeMap[change.RecordID] = append(changeMap[change.RecordID], change)
+	}
+
+	return recordMap, changeMap, nil
+}
@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"kakeibo-web-server/domain"
+
+	"golang.org/x/xerrors"
+)
+
+func (u *Usecase) CreateRecordCategory(ctx context.Context, userID domain.UserID, recordType domain.RecordType, name string, parentID *domain.RecordCategoryID, idempotencyKey *string) (*domain.RecordCategory, error) {
+	category, err := domain.NewRecordCategory(userID, recordType, name, parentID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateRecordCategory, []any{recordType, name, parentID}, string(category.ID), func(ctx context.Context) error {
+		if parentID != nil {
+			parent, err := u.repo.RecordCategory.GetByID(ctx, userID, *parentID)
+			if err != nil {
+				return xerrors.Errorf("failed to get parent record category: %w", err)
+			}
+
+			// 新規作成のため子孫は存在せず、循環のチェックは不要
+			err = category.ValidateParent(parent, nil)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		_, err := u.repo.RecordCategory.Insert(ctx, category)
+		if err != nil {
+			return xerrors.Errorf("failed to insert record category: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if replayed {
+		category, err = u.repo.RecordCategory.GetByID(ctx, userID, domain.RecordCategoryID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get record category: %w", err)
+		}
+	}
+
+	return category, nil
+}
+
+func (u *Usecase) UpdateRecordCategory(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID, name string, parentID *domain.RecordCategoryID) (*domain.RecordCategory, error) {
+	var category *domain.RecordCategory
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getCategory, err := u.repo.RecordCategory.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf("failed to get record category: %w", err)
+		}
+		category = getCategory
+
+		if parentID != nil {
+			parent, err := u.repo.RecordCategory.GetByID(ctx, userID, *parentID)
+			if err != nil {
+				return xerrors.Errorf("failed to get parent record category: %w", err)
+			}
+
+			categories, err := u.repo.RecordCategory.GetMultiByUserIDAndRecordType(ctx, userID, &category.RecordType)
+			if err != nil {
+				return xerrors.Errorf("failed to get record categories: %w", err)
+			}
+
+			err = category.ValidateParent(parent, categories)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		category.Name = name
+		category.ParentID = parentID
+
+		_, err = u.repo.RecordCategory.Update(ctx, category)
+		if err != nil {
+			return xerrors.Errorf("failed to update record category: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+// DeleteRecordCategory は分類を削除する。子の分類は削除した分類の親に付け替え、分類が設定されていたRecordは未分類になる
+func (u *Usecase) DeleteRecordCategory(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID) (domain.RecordCategoryID, error) {
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		category, err := u.repo.RecordCategory.GetByID(ctx, userID, id)
+		if err != nil {
+			return xerrors.Errorf("failed to get record category: %w", err)
+		}
+
+		err = u.repo.RecordCategory.UpdateParentIDByParentID(ctx, userID, category.ID, category.ParentID)
+		if err != nil {
+			return xerrors.Errorf("failed to move child record categories: %w", err)
+		}
+
+		_, err = u.repo.RecordCategory.Delete(ctx, userID, category.ID)
+		if err != nil {
+			return xerrors.Errorf("failed to delete record category: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", xerrors.Errorf(": %w", err)
+	}
+
+	return id, nil
+}
+
+func (u *Usecase) GetRecordCategoryByID(ctx context.Context, userID domain.UserID, id domain.RecordCategoryID) (*domain.RecordCategory, error) {
+	category, err := u.repo.RecordCategory.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return category, nil
+}
+
+func (u *Usecase) GetRecordCategoriesByIDs(ctx context.Context, userID domain.UserID, ids []domain.RecordCategoryID) (domain.RecordCategories, error) {
+	categories, err := u.repo.RecordCategory.GetMultiByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return categories, nil
+}
+
+func (u *Usecase) GetRecordCategoriesByRecordType(ctx context.Context, userID domain.UserID, recordType *domain.RecordType) (domain.RecordCategories, error) {
+	categories, err := u.repo.RecordCategory.GetMultiByUserIDAndRecordType(ctx, userID, recordType)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return categories, nil
+}
+
+func (u *Usecase) GetRecordCategoriesByParentIDs(ctx context.Context, userID domain.UserID, parentIDs []domain.RecordCategoryID) (domain.RecordCategories, error) {
+	categories, err := u.repo.RecordCategory.GetMultiByParentIDs(ctx, userID, parentIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return categories, nil
+}
+
+// GetRecordCategorySummaries は条件に一致するRecordの金額を分類の木構造に沿って集計する
+// 支出の場合も金額は正の値で返す。maxDepthを指定した場合はその深さまでの分類のみ返す（集計額には深い分類の金額も含む）
+func (u *Usecase) GetRecordCategorySummaries(ctx context.Context, userID domain.UserID, recordType domain.RecordType, cond *domain.RecordCondition, maxDepth *int) ([]*domain.RecordCategorySummary, error) {
+	categories, err := u.repo.RecordCategory.GetMultiByUserIDAndRecordType(ctx, userID, &recordType)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record categories: %w", err)
+	}
+
+	amounts, err := u.repo.Record.SumAmountByConditionGroupByCategory(ctx, userID, cond, recordType)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sum record amount by category: %w", err)
+	}
+
+	if recordType == domain.RecordTypeExpense {
+		for _, amount := range amounts {
+			amount.Amount = -amount.Amount
+		}
+	}
+
+	summaries := make([]*domain.RecordCategorySummary, 0, len(categories)+1)
+	for _, summary := range categories.Summaries(amounts) {
+		if maxDepth != nil && summary.Depth > *maxDepth {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// ConvertTagsToRecordCategories は指定したTagと同じ名前の分類を作成し、そのTagが付与された未分類のRecordに分類を設定する
+// 同じ親の下に同名の分類がすでにある場合はその分類を使う。複数のTagが付与されたRecordには、tagIDsで先に指定したTagの分類が設定される
+// deleteTagsがtrueの場合は変換したTagを削除する
+func (u *Usecase) ConvertTagsToRecordCategories(ctx context.Context, userID domain.UserID, tagIDs []domain.TagID, recordType domain.RecordType, parentID *domain.RecordCategoryID, deleteTags bool) ([]*domain.RecordCategory, int, error) {
+	categories := make([]*domain.RecordCategory, 0, len(tagIDs))
+	assignedCount := 0
+
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		tags, err := u.repo.Tag.GetMultiByIDs(ctx, userID, tagIDs)
+		if err != nil {
+			return xerrors.Errorf("failed to get tags: %w", err)
+		}
+		tagMap := make(map[domain.TagID]*domain.Tag, len(tags))
+		for _, tag := range tags {
+			tagMap[tag.ID] = tag
+		}
+
+		var parent *domain.RecordCategory
+		if parentID != nil {
+			parent, err = u.repo.RecordCategory.GetByID(ctx, userID, *parentID)
+			if err != nil {
+				return xerrors.Errorf("failed to get parent record category: %w", err)
+			}
+		}
+
+		for _, tagID := range tagIDs {
+			tag, ok := tagMap[tagID]
+			if !ok {
+				return xerrors.Errorf("tag %s not found: %w", tagID, domain.ErrEntityNotFound)
+			}
+
+			category, err := u.repo.RecordCategory.GetByRecordTypeAndParentIDAndName(ctx, userID, recordType, parentID, tag.Name)
+			if err != nil && !errors.Is(err, domain.ErrEntityNotFound) {
+				return xerrors.Errorf("failed to get record category: %w", err)
+			}
+			if category == nil {
+				category, err = domain.NewRecordCategory(userID, recordType, tag.Name, parentID)
+				if err != nil {
+					return xerrors.Errorf(": %w", err)
+				}
+				err = category.ValidateParent(parent, nil)
+				if err != nil {
+					return xerrors.Errorf(": %w", err)
+				}
+
+				_, err = u.repo.RecordCategory.Insert(ctx, category)
+				if err != nil {
+					return xerrors.Errorf("failed to insert record category: %w", err)
+				}
+			}
+			categories = append(categories, category)
+
+			count, err := u.repo.Record.UpdateCategoryIDByTagID(ctx, userID, tag.ID, recordType, category.ID)
+			if err != nil {
+				return xerrors.Errorf("failed to assign record category: %w", err)
+			}
+			assignedCount += count
+
+			if deleteTags {
+				_, err = u.repo.Tag.Delete(ctx, userID, tag.ID)
+				if err != nil {
+					return xerrors.Errorf("failed to delete tag: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, xerrors.Errorf(": %w", err)
+	}
+
+	return categories, assignedCount, nil
+}
+
+// updateRecordCategory はRecordの分類を変更する。categoryIDがnilの場合はclearCategoryがtrueのときのみ未分類にする
+func (u *Usecase) updateRecordCategory(ctx context.Context, userID domain.UserID, record *domain.Record, categoryID *domain.RecordCategoryID, clearCategory bool) error {
+	if categoryID != nil && clearCategory {
+		return xerrors.Errorf("categoryID and clearCategory cannot be specified together: %w", domain.ErrInvalidRecordCategory)
+	}
+	if categoryID == nil && !clearCategory {
+		return nil
+	}
+
+	category, err := u.getRecordCategoryForRecord(ctx, userID, categoryID)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	err = record.SetCategory(category)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	return nil
+}
+
+// getRecordCategoryForRecord はRecordに設定する分類を取得する。categoryIDがnilの場合はnilを返す
+func (u *Usecase) getRecordCategoryForRecord(ctx context.Context, userID domain.UserID, categoryID *domain.RecordCategoryID) (*domain.RecordCategory, error) {
+	if categoryID == nil {
+		return nil, nil
+	}
+
+	category, err := u.repo.RecordCategory.GetByID(ctx, userID, *categoryID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record category: %w", err)
+	}
+
+	return category, nil
+}
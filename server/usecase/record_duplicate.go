@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// GetDuplicateCandidates はfromからtoまでのRecordから、重複して登録された可能性がある組を探す
+func (u *Usecase) GetDuplicateCandidates(ctx context.Context, userID domain.UserID, from, to time.Time) ([]*domain.DuplicateCandidate, error) {
+	if to.Before(from) || to.Sub(from) > domain.DuplicateCandidateMaxRange {
+		return nil, xerrors.Errorf("range must be within %s: %w", domain.DuplicateCandidateMaxRange, domain.ErrInvalidDateRange)
+	}
+
+	records, err := u.repo.Record.GetMultiByAtRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	changes, err := u.repo.AssetChange.GetMultiByRecordIDs(ctx, userID, records.IDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return domain.FindDuplicateCandidates(records, changes), nil
+}
+
+// MergeRecords はdropIDsのRecordに付与されたTagをkeepIDのRecordに付与し、dropIDsのRecordを削除する
+func (u *Usecase) MergeRecords(ctx context.Context, userID domain.UserID, keepID domain.RecordID, dropIDs []domain.RecordID) (*domain.Record, error) {
+	mergeIDs := make([]domain.RecordID, 0, len(dropIDs))
+	for _, id := range domain.UniqueRecordIDs(dropIDs) {
+		if id != keepID {
+			mergeIDs = append(mergeIDs, id)
+		}
+	}
+	if len(mergeIDs) == 0 {
+		return nil, xerrors.Errorf("dropIDs must have at least one record other than keepID: %w", domain.ErrInvalidRecordMerge)
+	}
+
+	var keep *domain.Record
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		record, err := u.repo.Record.GetByID(ctx, userID, keepID)
+		if err != nil {
+			return xerrors.Errorf("failed to get record to keep: %w", err)
+		}
+		keep = record
+
+		drops, err := u.repo.Record.GetMultiByIDs(ctx, userID, mergeIDs)
+		if err != nil {
+			return xerrors.Errorf("failed to get records to drop: %w", err)
+		}
+		if len(drops) != len(mergeIDs) {
+			return xerrors.Errorf("some records to drop not found: %w", domain.ErrEntityNotFound)
+		}
+
+		tags, err := u.repo.Tag.GetMultiWithRecordIDByRecordIDs(ctx, userID, mergeIDs)
+		if err != nil {
+			return xerrors.Errorf("failed to get tags of records to drop: %w", err)
+		}
+		tagIDs := make([]domain.TagID, 0, len(tags))
+		for _, tag := range tags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		tagIDs = domain.UniqueTagIDs(tagIDs)
+
+		if len(tagIDs) > 0 {
+			err = u.repo.RecordTag.InsertMulti(ctx, []domain.RecordID{keep.ID}, tagIDs)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+
+			// Tagの変更もRecordの更新として扱い、versionを進める
+			_, err = u.repo.Record.Update(ctx, keep)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+		}
+
+		_, err = u.repo.Record.DeleteByIDs(ctx, userID, mergeIDs)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		var earliestAt time.Time
+		for _, drop := range drops {
+			earliestAt = domain.EarliestAt(earliestAt, drop.At)
+		}
+		err = u.repo.TotalAssetsSnapshot.InvalidateByUserIDAndSince(ctx, userID, earliestAt)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return keep, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+
+	"golang.org/x/xerrors"
+)
+
+// SetRecordItems はRecordの明細を置き換える。空のリストを指定すると明細なしにする
+// 明細の合計はRecordの金額と一致しなければならない。taxModeを指定した場合は単価の表し方も変更する
+func (u *Usecase) SetRecordItems(ctx context.Context, userID domain.UserID, recordID domain.RecordID, params []*domain.RecordItemParam, taxMode *domain.TaxMode, expectedVersion *int) (*domain.Record, error) {
+	var record *domain.Record
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getRecord, err := u.repo.Record.GetByID(ctx, userID, recordID)
+		if err != nil {
+			return xerrors.Errorf("failed to get record by ID: %w", err)
+		}
+		err = domain.ValidateVersion(getRecord.Version, expectedVersion)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		record = getRecord
+
+		// 明細を保存した時点の端数処理で税額を求め、後からユーザーの設定を変えても明細の合計が変わらないようにする
+		rounding, err := u.getTaxRounding(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		mode := record.TaxMode
+		if taxMode != nil {
+			mode = *taxMode
+		}
+		err = record.SetTax(record.TaxRate, mode, rounding)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		items, err := domain.NewRecordItems(userID, recordID, params)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		assetChanges, err := u.repo.AssetChange.GetMultiByRecordID(ctx, userID, recordID)
+		if err != nil {
+			return xerrors.Errorf("failed to get asset changes by record ID: %w", err)
+		}
+		err = items.Validate(record, assetChanges)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		items.SetInclusiveAmounts(record.TaxMode, record.TaxRounding)
+
+		tagNames := make([]string, 0)
+		for _, param := range params {
+			tagNames = append(tagNames, param.TagNames...)
+		}
+		tags, err := u.GetOrCreateTagsByName(ctx, userID, tagNames)
+		if err != nil {
+			return xerrors.Errorf("failed to get or create tags: %w", err)
+		}
+		tagMap := make(map[string]*domain.Tag, len(tags))
+		for _, tag := range tags {
+			tagMap[tag.Name] = tag
+		}
+		for i, item := range items {
+			for _, name := range domain.NormalizeTagNames(params[i].TagNames) {
+				item.Tags = append(item.Tags, tagMap[name])
+			}
+		}
+
+		err = u.repo.RecordItem.DeleteByRecordID(ctx, userID, recordID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.repo.RecordItem.InsertMulti(ctx, items)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		// 明細の変更もRecordの更新として他の端末での変更と競合させる
+		_, err = u.repo.Record.Update(ctx, record)
+		if err != nil {
+			return xerrors.Errorf("failed to update record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return record, nil
+}
+
+// validateRecordItems は更新後のRecordの金額・種別が登録済みの明細と矛盾しないかを検証する
+// 税の設定が変わっても集計に使う税込金額がずれないよう、明細の税込金額も求め直す
+func (u *Usecase) validateRecordItems(ctx context.Context, userID domain.UserID, record *domain.Record) error {
+	items, err := u.repo.RecordItem.GetMultiByRecordIDs(ctx, userID, []domain.RecordID{record.ID})
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	assetChanges, err := u.repo.AssetChange.GetMultiByRecordID(ctx, userID, record.ID)
+	if err != nil {
+		return xerrors.Errorf("failed to get asset changes by record ID: %w", err)
+	}
+	err = items.Validate(record, assetChanges)
+	if err != nil {
+		return xerrors.Errorf("update items together with amount: %w", err)
+	}
+
+	items.SetInclusiveAmounts(record.TaxMode, record.TaxRounding)
+	err = u.repo.RecordItem.UpdateInclusiveAmounts(ctx, items)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	return nil
+}
+
+func (u *Usecase) GetRecordItemsByRecordIDs(ctx context.Context, userID domain.UserID, recordIDs []domain.RecordID) (domain.RecordItems, error) {
+	items, err := u.repo.RecordItem.GetMultiByRecordIDs(ctx, userID, recordIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return items, nil
+}
+
+// GetTagSummaries は条件に一致するRecordの金額をTagごとに集計し、金額の大きい順に返す
+// 明細があるRecordは明細ごとに、明細のTagへ金額を割り当てる
+func (u *Usecase) GetTagSummaries(ctx context.Context, userID domain.UserID, recordType domain.RecordType, cond *domain.RecordCondition) ([]*domain.TagSummary, error) {
+	amounts, err := u.repo.Record.SumAmountByConditionGroupByTag(ctx, userID, cond, recordType)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	tagIDs := make([]domain.TagID, 0, len(amounts))
+	for _, amount := range amounts {
+		if amount.TagID != nil {
+			tagIDs = append(tagIDs, *amount.TagID)
+		}
+	}
+	tags, err := u.repo.Tag.GetMultiByIDs(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	tagMap := make(map[domain.TagID]*domain.Tag, len(tags))
+	for _, tag := range tags {
+		tagMap[tag.ID] = tag
+	}
+
+	summaries := make([]*domain.TagSummary, 0, len(amounts))
+	for _, amount := range amounts {
+		summary := &domain.TagSummary{
+			Amount:      amount.Amount,
+			RecordCount: amount.RecordCount,
+		}
+		if amount.TagID != nil {
+			summary.Tag = tagMap[*amount.TagID]
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
@@ -3,25 +3,83 @@ package usecase
 import (
 	"context"
 	"kakeibo-web-server/domain"
+	"time"
 
 	"golang.org/x/xerrors"
 )
 
-func (u *Usecase) CreateTag(ctx context.Context, userID domain.UserID, name string) (*domain.Tag, error) {
-	createdTag, err := u.repo.Tag.Insert(ctx, domain.NewTag(userID, name))
+// CreateTag はTagを作成する。color・iconがnilの場合は設定しない。並び順は既存のTagの末尾にする
+func (u *Usecase) CreateTag(ctx context.Context, userID domain.UserID, name string, color *string, icon *string, idempotencyKey *string) (*domain.Tag, error) {
+	tag, err := domain.NewTag(userID, name)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
 	}
+	if color != nil {
+		err := tag.SetColor(*color)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+	if icon != nil {
+		tag.SetIcon(*icon)
+	}
+
+	resourceID, replayed, err := u.runIdempotent(ctx, userID, idempotencyKey, domain.IdempotentOperationCreateTag, []any{name, color, icon}, string(tag.ID), func(ctx context.Context) error {
+		maxSortOrder, err := u.repo.Tag.GetMaxSortOrderByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		tag.SortOrder = maxSortOrder + 1
+
+		_, err = u.repo.Tag.Insert(ctx, tag)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if replayed {
+		tag, err = u.repo.Tag.GetByID(ctx, userID, domain.TagID(resourceID))
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
 
-	return createdTag, nil
+	return tag, nil
 }
 
-func (u *Usecase) UpdateTag(ctx context.Context, userID domain.UserID, id domain.TagID, name string) (*domain.Tag, error) {
-	tag := &domain.Tag{
-		ID:     id,
-		UserID: userID,
-		Name:   name,
+// UpdateTag はTagを更新する。color・icon・archivedがnilの場合は変更せず、color・iconが空文字の場合は削除する
+// expectedVersionを指定した場合は、Tagがそのバージョンから更新されていない場合のみ更新する
+func (u *Usecase) UpdateTag(ctx context.Context, userID domain.UserID, id domain.TagID, name string, color *string, icon *string, archived *bool, expectedVersion *int) (*domain.Tag, error) {
+	tag, err := u.repo.Tag.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	err = domain.ValidateVersion(tag.Version, expectedVersion)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	err = tag.Rename(name)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if color != nil {
+		err = tag.SetColor(*color)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
 	}
+	if icon != nil {
+		tag.SetIcon(*icon)
+	}
+	if archived != nil {
+		tag.Archived = *archived
+	}
+
 	updatedTag, err := u.repo.Tag.Update(ctx, tag)
 	if err != nil {
 		return nil, xerrors.Errorf(": %w", err)
@@ -29,8 +87,54 @@ func (u *Usecase) UpdateTag(ctx context.Context, userID domain.UserID, id domain
 	return updatedTag, nil
 }
 
-func (u *Usecase) GetTagsByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID) ([]*domain.Tag, *domain.PageInfo, error) {
-	tags, pageInfo, err := u.repo.Tag.GetMultiByUserID(ctx, pageParam, userID)
+// ReorderTags はidsの順にTagの並び順を1から振り直す。指定しなかったTagは現在の順のまま、指定したTagの後ろに並べる
+func (u *Usecase) ReorderTags(ctx context.Context, userID domain.UserID, ids []domain.TagID) (domain.Tags, error) {
+	ids = domain.UniqueTagIDs(ids)
+
+	var tags domain.Tags
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		getTags, err := u.repo.Tag.GetMultiByIDs(ctx, userID, ids)
+		if err != nil {
+			return xerrors.Errorf("failed to get tags: %w", err)
+		}
+		if len(getTags) != len(ids) {
+			return xerrors.Errorf("some tags not found: %w", domain.ErrEntityNotFound)
+		}
+
+		tagMap := make(map[domain.TagID]*domain.Tag, len(getTags))
+		for _, tag := range getTags {
+			tagMap[tag.ID] = tag
+		}
+
+		tags = make(domain.Tags, 0, len(ids))
+		for i, id := range ids {
+			tag := tagMap[id]
+			tag.SortOrder = i + 1
+			tag.Version++
+
+			err = u.repo.Tag.UpdateSortOrder(ctx, userID, tag.ID, tag.SortOrder)
+			if err != nil {
+				return xerrors.Errorf(": %w", err)
+			}
+			tags = append(tags, tag)
+		}
+
+		err = u.repo.Tag.ShiftSortOrderExcludingIDs(ctx, userID, ids, len(ids))
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return tags, nil
+}
+
+func (u *Usecase) GetTagsByUserID(ctx context.Context, pageParam *domain.PageParam, userID domain.UserID, includeArchived bool) ([]*domain.Tag, *domain.PageInfo, error) {
+	tags, pageInfo, err := u.repo.Tag.GetMultiByUserID(ctx, pageParam, userID, includeArchived)
 	if err != nil {
 		return nil, nil, xerrors.Errorf(": %w", err)
 	}
@@ -38,7 +142,29 @@ func (u *Usecase) GetTagsByUserID(ctx context.Context, pageParam *domain.PagePar
 	return tags, pageInfo, nil
 }
 
-func (u *Usecase) DeleteTag(ctx context.Context, userID domain.UserID, tagID domain.TagID) (domain.TagID, error) {
+func (u *Usecase) CountTagsByUserID(ctx context.Context, userID domain.UserID, includeArchived bool) (int, error) {
+	count, err := u.repo.Tag.CountByUserID(ctx, userID, includeArchived)
+	if err != nil {
+		return 0, xerrors.Errorf(": %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteTag はTagを削除する。reassignToを指定した場合は、削除するTagが付与されていたRecordにreassignToのTagを付与する
+func (u *Usecase) DeleteTag(ctx context.Context, userID domain.UserID, tagID domain.TagID, reassignTo *domain.TagID) (domain.TagID, error) {
+	if reassignTo != nil && *reassignTo == tagID {
+		return "", xerrors.Errorf("tag %s cannot be reassigned to itself: %w", tagID, domain.ErrInvalidTag)
+	}
+	if reassignTo != nil {
+		_, err := u.MergeTags(ctx, userID, []domain.TagID{tagID}, *reassignTo)
+		if err != nil {
+			return "", xerrors.Errorf(": %w", err)
+		}
+
+		return tagID, nil
+	}
+
 	deletedTag, err := u.repo.Tag.Delete(ctx, userID, tagID)
 	if err != nil {
 		return "", xerrors.Errorf(": %w", err)
@@ -47,8 +173,62 @@ func (u *Usecase) DeleteTag(ctx context.Context, userID domain.UserID, tagID dom
 	return deletedTag, nil
 }
 
+// MergeTags はsourceIDsのTagが付与されたRecord・明細にtargetIDのTagを付与し、sourceIDsのTagを削除する
+func (u *Usecase) MergeTags(ctx context.Context, userID domain.UserID, sourceIDs []domain.TagID, targetID domain.TagID) (*domain.Tag, error) {
+	var target *domain.Tag
+	err := u.repo.RunInTx(ctx, func(ctx context.Context) error {
+		targets, err := u.repo.Tag.GetMultiByIDs(ctx, userID, []domain.TagID{targetID})
+		if err != nil {
+			return xerrors.Errorf("failed to get target tag: %w", err)
+		}
+		if len(targets) == 0 {
+			return xerrors.Errorf("target tag %s not found: %w", targetID, domain.ErrEntityNotFound)
+		}
+		target = targets[0]
+
+		mergeIDs := make([]domain.TagID, 0, len(sourceIDs))
+		for _, sourceID := range sourceIDs {
+			if sourceID != targetID {
+				mergeIDs = append(mergeIDs, sourceID)
+			}
+		}
+
+		sources, err := u.repo.Tag.GetMultiByIDs(ctx, userID, mergeIDs)
+		if err != nil {
+			return xerrors.Errorf("failed to get source tags: %w", err)
+		}
+		if len(sources) != len(domain.UniqueTagIDs(mergeIDs)) {
+			return xerrors.Errorf("some source tags not found: %w", domain.ErrEntityNotFound)
+		}
+
+		err = u.repo.RecordTag.MoveTagIDs(ctx, sources.IDs(), target.ID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+		err = u.repo.RecordItem.MoveTagIDs(ctx, sources.IDs(), target.ID)
+		if err != nil {
+			return xerrors.Errorf(": %w", err)
+		}
+
+		for _, source := range sources {
+			_, err = u.repo.Tag.Delete(ctx, userID, source.ID)
+			if err != nil {
+				return xerrors.Errorf("failed to delete source tag: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return target, nil
+}
+
 func (u *Usecase) GetOrCreateTagsByName(ctx context.Context, userID domain.UserID, names []string) ([]*domain.Tag, error) {
 	tags := make([]*domain.Tag, 0, len(names))
+	names = domain.NormalizeTagNames(names)
 
 	existTags, err := u.repo.Tag.GetMultiByNames(ctx, userID, names)
 	if err != nil {
@@ -56,8 +236,20 @@ func (u *Usecase) GetOrCreateTagsByName(ctx context.Context, userID domain.UserI
 	}
 	tags = append(tags, existTags...)
 
-	newTags := domain.NewTagsNotExist(userID, existTags, names)
-	for _, tag := range newTags {
+	newTags, err := domain.NewTagsNotExist(userID, existTags, names)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	if len(newTags) == 0 {
+		return tags, nil
+	}
+
+	maxSortOrder, err := u.repo.Tag.GetMaxSortOrderByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	for i, tag := range newTags {
+		tag.SortOrder = maxSortOrder + i + 1
 		createdTag, err := u.repo.Tag.Insert(ctx, tag)
 		if err != nil {
 			return nil, xerrors.Errorf(": %w", err)
@@ -80,3 +272,47 @@ func (u *Usecase) GetTagsWithRecordIDByRecordIDs(ctx context.Context, userID dom
 
 	return tagWithRecordIDs, nil
 }
+
+func (u *Usecase) GetTagStatsByTagIDs(ctx context.Context, userID domain.UserID, tagIDs []domain.TagID) ([]*domain.TagStat, error) {
+	stats, err := u.repo.Tag.GetStatsByTagIDs(ctx, userID, tagIDs)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return stats, nil
+}
+
+// SuggestTags は入力中の文字列、Recordのタイトル、Assetと過去の利用履歴から、付与する可能性の高いTagを返す
+func (u *Usecase) SuggestTags(ctx context.Context, userID domain.UserID, prefix string, title string, assetID *domain.AssetID, limit int) (domain.Tags, error) {
+	if limit <= 0 || limit > domain.TagSuggestionMaxLimit {
+		return nil, xerrors.Errorf("limit must be between 1 and %d: %w", domain.TagSuggestionMaxLimit, domain.ErrInvalidPageParam)
+	}
+
+	tags, err := u.repo.Tag.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	signals := &domain.TagSuggestionSignals{}
+
+	signals.Stats, err = u.repo.Tag.GetStatsByUserID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	if len(domain.SearchTerms(title)) > 0 {
+		signals.TitleCounts, err = u.repo.Tag.CountUsageBySimilarTitle(ctx, userID, title)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+
+	if assetID != nil {
+		signals.AssetCounts, err = u.repo.Tag.CountUsageByAssetID(ctx, userID, *assetID)
+		if err != nil {
+			return nil, xerrors.Errorf(": %w", err)
+		}
+	}
+
+	return domain.RankTagSuggestions(tags, prefix, signals, time.Now(), limit), nil
+}
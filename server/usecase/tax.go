@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"kakeibo-web-server/domain"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// UpdateTaxRounding はユーザーの消費税額の端数処理を変更する
+// 既存のRecordには税率・明細を設定した時点の端数処理が記録されているため、変更後に税率・明細を設定したRecordから適用される
+func (u *Usecase) UpdateTaxRounding(ctx context.Context, userID domain.UserID, rounding domain.TaxRounding) (*domain.User, error) {
+	if !rounding.IsValid() {
+		return nil, xerrors.Errorf("tax rounding %s is not supported: %w", rounding, domain.ErrInvalidTax)
+	}
+
+	user, err := u.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	user.TaxRounding = rounding
+
+	_, err = u.repo.User.UpdateTaxRounding(ctx, user)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return user, nil
+}
+
+// GetTaxSummary はfrom以降to未満の収入・支出の消費税を税率ごとに集計する
+func (u *Usecase) GetTaxSummary(ctx context.Context, userID domain.UserID, from, to time.Time) (*domain.TaxSummary, error) {
+	if !from.Before(to) || to.Sub(from) > domain.TaxSummaryMaxRange {
+		return nil, xerrors.Errorf("range must be within %s: %w", domain.TaxSummaryMaxRange, domain.ErrInvalidDateRange)
+	}
+
+	rounding, err := u.getTaxRounding(ctx, userID)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	records, err := u.repo.Record.GetMultiTaxableByAtRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	changes, err := u.repo.AssetChange.GetMultiByRecordIDs(ctx, userID, records.IDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+	items, err := u.repo.RecordItem.GetMultiByRecordIDs(ctx, userID, records.IDs())
+	if err != nil {
+		return nil, xerrors.Errorf(": %w", err)
+	}
+
+	return domain.NewTaxSummary(records, changes, items, rounding), nil
+}
+
+// updateRecordTaxRate はRecordの税率を変更し、その時点の端数処理を記録する。taxRateがnilの場合はclearTaxRateがtrueのときのみ課税対象外にする
+func (u *Usecase) updateRecordTaxRate(ctx context.Context, userID domain.UserID, record *domain.Record, taxRate *domain.TaxRate, clearTaxRate bool) error {
+	if taxRate != nil && clearTaxRate {
+		return xerrors.Errorf("taxRate and clearTaxRate cannot be specified together: %w", domain.ErrInvalidTax)
+	}
+	if taxRate == nil && !clearTaxRate {
+		return nil
+	}
+
+	rounding, err := u.getTaxRounding(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+	err = record.SetTax(taxRate, record.TaxMode, rounding)
+	if err != nil {
+		return xerrors.Errorf(": %w", err)
+	}
+
+	return nil
+}
+
+func (u *Usecase) getTaxRounding(ctx context.Context, userID domain.UserID) (domain.TaxRounding, error) {
+	user, err := u.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		return "", xerrors.Errorf("failed to get user: %w", err)
+	}
+
+	return user.TaxRounding, nil
+}
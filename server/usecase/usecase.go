@@ -1,13 +1,18 @@
 package usecase
 
-import "kakeibo-web-server/repository"
+import (
+	"kakeibo-web-server/lib/storage"
+	"kakeibo-web-server/repository"
+)
 
 type Usecase struct {
-	repo *repository.Repository
+	repo    *repository.Repository
+	storage storage.Storage
 }
 
-func NewUsecase(repository *repository.Repository) *Usecase {
+func NewUsecase(repository *repository.Repository, storage storage.Storage) *Usecase {
 	return &Usecase{
-		repo: repository,
+		repo:    repository,
+		storage: storage,
 	}
 }